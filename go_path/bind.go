@@ -0,0 +1,66 @@
+package go_path
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Bind returns a copy of p in which every map key has been converted into a key of the map's key type, found by
+// following p through the type t. This resolves the literals of parsed typed map keys, such as [(42)], into Go values.
+// Binding stops at interface types, as their contents are only known at run time; components past that point are
+// copied as they are.
+func Bind(p Pather, t reflect.Type) (PathMutator, error) {
+	bound := NewRoot()
+	parts := components(p)
+	for i, component := range parts {
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() == reflect.Interface {
+			bound.Append(parts[i:]...)
+			return bound, nil
+		}
+		switch c := component.(type) {
+		case *pathStructInstanceVariable:
+			if t.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("unable to bind \"%s\": %s is not a struct", p.String(), t.String())
+			}
+			field, ok := t.FieldByName(c.variableName)
+			if !ok {
+				return nil, fmt.Errorf("unable to bind \"%s\": %s has no field %s", p.String(), t.String(), c.variableName)
+			}
+			t = field.Type
+		case *pathArrayInstanceVariable, *pathKeyedElement:
+			if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+				return nil, fmt.Errorf("unable to bind \"%s\": %s is not a slice or array", p.String(), t.String())
+			}
+			t = t.Elem()
+		case mapKeyer:
+			if t.Kind() != reflect.Map {
+				return nil, fmt.Errorf("unable to bind \"%s\": %s is not a map", p.String(), t.String())
+			}
+			key, ok := c.keyFor(t.Key())
+			if !ok {
+				return nil, fmt.Errorf("unable to bind \"%s\": %s cannot be used with keys of type %s", p.String(), c.String(), t.Key().String())
+			}
+			if _, isTyped := c.(*pathTypedMapKey); isTyped {
+				component = NewMapKeyOf(key.Interface())
+			}
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("unable to bind \"%s\": %s does not locate a single value, see ExpandType", p.String(), c.String())
+		}
+		bound.Append(component)
+	}
+	return bound, nil
+}
+
+// ParseTyped parses a path and binds it to the type t, see Bind
+func ParseTyped(reader io.Reader, t reflect.Type) (Pather, error) {
+	p, err := Parse(reader)
+	if err != nil {
+		return nil, err
+	}
+	return Bind(p, t)
+}
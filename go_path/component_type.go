@@ -1,10 +1,51 @@
 package go_path
 
-type componentType uint8
+// ComponentKind identifies what a Componenter locates within its parent
+type ComponentKind uint8
 
 const (
-	componentTypeInvalid componentType = iota
-	componentTypeStruct
-	componentTypeMap
-	componentTypeArray
+	ComponentKindInvalid ComponentKind = iota
+	// ComponentKindStruct a struct field, see FieldComponenter
+	ComponentKindStruct
+	// ComponentKindMap a map key, see MapKeyComponenter
+	ComponentKindMap
+	// ComponentKindArray a slice or array index, see IndexComponenter
+	ComponentKindArray
+	// ComponentKindArrayRange a range of slice or array indexes, see RangeComponenter
+	ComponentKindArrayRange
+	// ComponentKindWildcard every child of a value, see WildcardComponenter
+	ComponentKindWildcard
+	// ComponentKindDescendant named fields and map keys at any depth, see DescendantComponenter
+	ComponentKindDescendant
+	// ComponentKindFilter the elements that satisfy an expression, see FilterComponenter
+	ComponentKindFilter
+	// ComponentKindKeyedElement the element of a slice or array with a field value, see KeyedElementComponenter
+	ComponentKindKeyedElement
+	// ComponentKindUnion several alternative children, see UnionComponenter
+	ComponentKindUnion
 )
+
+func (k ComponentKind) String() string {
+	switch k {
+	case ComponentKindStruct:
+		return "struct"
+	case ComponentKindMap:
+		return "map"
+	case ComponentKindArray:
+		return "array"
+	case ComponentKindArrayRange:
+		return "array range"
+	case ComponentKindWildcard:
+		return "wildcard"
+	case ComponentKindDescendant:
+		return "descendant"
+	case ComponentKindFilter:
+		return "filter"
+	case ComponentKindKeyedElement:
+		return "keyed element"
+	case ComponentKindUnion:
+		return "union"
+	default:
+		return "invalid"
+	}
+}
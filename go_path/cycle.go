@@ -0,0 +1,71 @@
+package go_path
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// CycleMode determines what happens when a walk finds a pointer, map or slice that it is already walking
+type CycleMode uint8
+
+const (
+	// CycleModeSkip visits the revisiting pointer, but does not descend into it again
+	CycleModeSkip CycleMode = iota
+	// CycleModeError stops the walk with a *CycleError
+	CycleModeError
+	// CycleModeReference visits the revisiting path with a Reference value instead of the pointer
+	CycleModeReference
+)
+
+// CycleError is returned when a walk configured with CycleModeError revisits a pointer
+type CycleError struct {
+	// Original is the path where the pointer was first seen
+	Original Pather
+	// Revisit is the path where the pointer was seen again
+	Revisit Pather
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("\"%s\" refers back to \"%s\"", e.Revisit.String(), e.Original.String())
+}
+
+// Reference marks a value that was already visited at Original. It is passed to a WalkFunc, wrapped in a reflect.Value,
+// when a walk configured with CycleModeReference revisits a pointer
+type Reference struct {
+	Original Pather
+}
+
+// pointerKey identifies the value a pointer points at, or the contents of a map or slice. The type is included, as a
+// struct and its first field share the same address, and so is the length of slices, as a slice shares its address
+// with shorter slices of the same array
+type pointerKey struct {
+	address uintptr
+	typ     reflect.Type
+	length  int
+}
+
+func newPointerKey(v reflect.Value) pointerKey {
+	key := pointerKey{
+		address: v.Pointer(),
+		typ:     v.Type(),
+	}
+	if v.Kind() == reflect.Slice {
+		key.length = v.Len()
+	}
+	return key
+}
+
+// isReference is true for pointers and maps that are not nil and slices that are not empty: the values that can
+// contain themselves, and that newPointerKey identifies. Pointers and slices to values of zero size are not, as they
+// cannot contain anything, and different ones may share the same address
+func isReference(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map:
+		return !v.IsNil()
+	case reflect.Ptr:
+		return !v.IsNil() && v.Type().Elem().Size() != 0
+	case reflect.Slice:
+		return v.Len() != 0 && v.Type().Elem().Size() != 0
+	}
+	return false
+}
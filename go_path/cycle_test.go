@@ -0,0 +1,150 @@
+package go_path
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+type testNode struct {
+	Name string
+	Prev *testNode
+	Next *testNode
+}
+
+func newTestList() *testNode {
+	first := &testNode{Name: "first"}
+	second := &testNode{Name: "second", Prev: first}
+	first.Next = second
+	return first
+}
+
+type testShared struct {
+	A *testWalkInner
+	B *testWalkInner
+}
+
+func TestWalk_Cycles(t *testing.T) {
+	cases := map[string]struct {
+		input    func() interface{}
+		opts     []WalkOption
+		expected []string
+	}{
+		"skip": {
+			input: func() interface{} {
+				return newTestList()
+			},
+			opts: []WalkOption{WalkFollowPointers()},
+			expected: []string{
+				"", "Name", "Prev", "Next", "Next.Name", "Next.Prev", "Next.Next",
+			},
+		},
+		"reference": {
+			input: func() interface{} {
+				return newTestList()
+			},
+			opts: []WalkOption{WalkFollowPointers(), WalkOnCycle(CycleModeReference), WalkLeavesOnly()},
+			expected: []string{
+				"Name", "Prev", "Next.Name", "Next.Prev -> ", "Next.Next",
+			},
+		},
+		"shared visited twice": {
+			input: func() interface{} {
+				inner := &testWalkInner{Value: 1}
+				return &testShared{A: inner, B: inner}
+			},
+			opts: []WalkOption{WalkFollowPointers(), WalkLeavesOnly()},
+			expected: []string{
+				"A.Value", "B.Value",
+			},
+		},
+		"shared visited once": {
+			input: func() interface{} {
+				inner := &testWalkInner{Value: 1}
+				return &testShared{A: inner, B: inner}
+			},
+			opts: []WalkOption{WalkFollowPointers(), WalkLeavesOnly(), WalkVisitOnce(), WalkOnCycle(CycleModeReference)},
+			expected: []string{
+				"A.Value", "B -> A",
+			},
+		},
+		"map containing itself": {
+			input: func() interface{} {
+				m := map[string]interface{}{"name": "a"}
+				m["self"] = m
+				return m
+			},
+			opts: []WalkOption{WalkSortedMapKeys(), WalkOnCycle(CycleModeReference)},
+			expected: []string{
+				"", "[\"name\"]", "[\"self\"] -> ",
+			},
+		},
+		"slice containing itself": {
+			input: func() interface{} {
+				s := []interface{}{"a", nil}
+				s[1] = s
+				return s
+			},
+			opts: []WalkOption{WalkOnCycle(CycleModeReference)},
+			expected: []string{
+				"", "[0]", "[1] -> ",
+			},
+		},
+		"shared map visited twice": {
+			input: func() interface{} {
+				shared := map[string]int{"a": 1}
+				return []map[string]int{shared, shared}
+			},
+			opts: []WalkOption{WalkLeavesOnly(), WalkVisitOnce()},
+			expected: []string{
+				"[0][\"a\"]", "[1][\"a\"]",
+			},
+		},
+	}
+
+	for caseName, c := range cases {
+		actual := make([]string, 0)
+		err := Walk(c.input(), func(p Pather, v reflect.Value) error {
+			if ref, ok := v.Interface().(Reference); ok {
+				actual = append(actual, p.String()+" -> "+ref.Original.String())
+			} else {
+				actual = append(actual, p.String())
+			}
+			return nil
+		}, c.opts...)
+		require.NoError(t, err, caseName)
+		assert.Equal(t, c.expected, actual, caseName)
+	}
+}
+
+func TestWalk_CycleError(t *testing.T) {
+	err := Walk(newTestList(), func(p Pather, v reflect.Value) error {
+		return nil
+	}, WalkFollowPointers(), WalkOnCycle(CycleModeError))
+	var cycleErr *CycleError
+	require.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, "", cycleErr.Original.String())
+	assert.Equal(t, "Next.Prev", cycleErr.Revisit.String())
+}
+
+type testZeroSize struct{}
+
+type testZeroSizeShared struct {
+	A     *testZeroSize
+	B     *testZeroSize
+	Empty []testZeroSize
+	More  []testZeroSize
+}
+
+func TestWalk_ZeroSizeIsNotRevisited(t *testing.T) {
+	root := &testZeroSizeShared{A: &testZeroSize{}, B: &testZeroSize{}, Empty: make([]testZeroSize, 1), More: make([]testZeroSize, 1)}
+	actual := make([]string, 0)
+	err := Walk(root, func(p Pather, v reflect.Value) error {
+		actual = append(actual, p.String())
+		return nil
+	}, WalkFollowPointers(), WalkVisitOnce(), WalkOnCycle(CycleModeError))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "A", "B", "Empty", "Empty[0]", "More", "More[0]"}, actual)
+}
@@ -0,0 +1,88 @@
+package go_path
+
+import "reflect"
+
+// deepCopy returns a copy of v that shares no pointers, slices or maps with v. Pointers, slices and maps that are shared
+// within v, or that form cycles, are shared within the copy as well. Un-exported struct fields are copied shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	return (&copier{copies: make(map[pointerKey]reflect.Value)}).copy(v)
+}
+
+type copier struct {
+	// copies of the pointers, slices and maps already copied
+	copies map[pointerKey]reflect.Value
+}
+
+func (c *copier) copy(v reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		key := newPointerKey(v)
+		if existing, ok := c.copies[key]; ok {
+			return existing
+		}
+		out := reflect.New(v.Type().Elem())
+		c.copies[key] = out
+		out.Elem().Set(c.copy(v.Elem()))
+		return out
+	case reflect.Interface:
+		out := reflect.New(v.Type()).Elem()
+		if !v.IsNil() {
+			out.Set(c.copy(v.Elem()))
+		}
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if field := out.Field(i); field.CanSet() {
+				field.Set(c.copy(v.Field(i)))
+			}
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		key := newPointerKey(v)
+		if existing, ok := c.copies[key]; ok {
+			return existing
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+		c.copies[key] = out
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		key := newPointerKey(v)
+		if existing, ok := c.copies[key]; ok {
+			return existing
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		c.copies[key] = out
+		for _, key := range v.MapKeys() {
+			out.SetMapIndex(key, c.copy(v.MapIndex(key)))
+		}
+		return out
+	default:
+		// v may be an element of a slice or map that is about to change, so the copy must not be addressed through it
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		return out
+	}
+}
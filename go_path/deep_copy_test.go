@@ -0,0 +1,23 @@
+package go_path
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestDeepCopy_Cycles(t *testing.T) {
+	m := map[string]interface{}{"name": "a"}
+	m["self"] = m
+	copied := deepCopy(reflect.ValueOf(m)).Interface().(map[string]interface{})
+	copied["name"] = "b"
+	assert.Equal(t, "a", m["name"])
+	assert.Equal(t, "b", copied["self"].(map[string]interface{})["name"])
+
+	s := []interface{}{"a", nil}
+	s[1] = s
+	copiedSlice := deepCopy(reflect.ValueOf(s)).Interface().([]interface{})
+	copiedSlice[0] = "b"
+	assert.Equal(t, "a", s[0])
+	assert.Equal(t, "b", copiedSlice[1].([]interface{})[0])
+}
@@ -0,0 +1,68 @@
+package go_path
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Delete removes the value identified by the path from its container.
+// A map key is removed from the map, a slice element is spliced out of the slice (shifting later elements down), and a
+// struct field is set to its zero value. Slice elements may be identified by index or by key, see NewKeyedElement.
+// Array elements cannot be removed. root must be a non-nil pointer.
+func Delete(root interface{}, p Pather) error {
+	rootValue, err := settableRoot(root)
+	if err != nil {
+		return err
+	}
+	parts := components(p)
+	if len(parts) == 0 {
+		return fmt.Errorf("unable to delete the root")
+	}
+	return editAt(rootValue, parts, NewRoot(), &setOptions{}, deleteIn)
+}
+
+func deleteIn(container reflect.Value, last Componenter, resolved PathMutator) error {
+	switch c := last.(type) {
+	case mapKeyer:
+		if container.Kind() != reflect.Map {
+			return newResolveError(ResolveReasonKindMismatch, container).locate(resolved, c)
+		}
+		key, ok := c.keyFor(container.Type().Key())
+		if !ok {
+			return newResolveError(ResolveReasonKindMismatch, container).locate(resolved, c)
+		}
+		if !container.MapIndex(key).IsValid() {
+			return newResolveError(ResolveReasonMissingMapKey, container).locate(resolved, c)
+		}
+		container.SetMapIndex(key, reflect.Value{})
+		return nil
+	case *pathArrayInstanceVariable, *pathKeyedElement:
+		if container.Kind() != reflect.Slice {
+			return newResolveError(ResolveReasonKindMismatch, container).locate(resolved, c)
+		}
+		if _, err := settableChild(container, c, resolved); err != nil {
+			return err
+		}
+		length := container.Len()
+		var index int
+		if keyed, ok := c.(*pathKeyedElement); ok {
+			index, _ = keyed.indexIn(container)
+		} else {
+			index, _ = c.(*pathArrayInstanceVariable).absoluteIndex(length)
+		}
+		reflect.Copy(container.Slice(index, length), container.Slice(index+1, length))
+		// clear the now unused last element so it does not hold on to references
+		container.Index(length - 1).Set(reflect.Zero(container.Type().Elem()))
+		container.SetLen(length - 1)
+		return nil
+	case *pathStructInstanceVariable:
+		field, err := settableChild(container, c, resolved)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	default:
+		return newResolveError(ResolveReasonUnsupportedComponent, container).locate(resolved, last)
+	}
+}
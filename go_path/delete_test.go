@@ -0,0 +1,110 @@
+package go_path
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDelete(t *testing.T) {
+	cases := map[string]struct {
+		input       func() *testConfig
+		path        func() Pather
+		expected    func() *testConfig
+		expectedErr bool
+	}{
+		"map key": {
+			input: func() *testConfig {
+				return &testConfig{Labels: map[string]string{"env": "prod", "team": "a"}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Labels"), NewMapKey("env"))
+			},
+			expected: func() *testConfig {
+				return &testConfig{Labels: map[string]string{"team": "a"}}
+			},
+		},
+		"missing map key": {
+			input: func() *testConfig {
+				return &testConfig{Labels: map[string]string{"team": "a"}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Labels"), NewMapKey("env"))
+			},
+			expectedErr: true,
+		},
+		"slice element": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {Host: "b"}, {Host: "c"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(1))
+			},
+			expected: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {Host: "c"}}}
+			},
+		},
+		"last slice element": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {Host: "b"}, {Host: "c"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(-1))
+			},
+			expected: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {Host: "b"}}}
+			},
+		},
+		"slice out of range": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(1))
+			},
+			expectedErr: true,
+		},
+		"struct field": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a", Port: 80}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(0), NewInstanceVariableNamed("Port"))
+			},
+			expected: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}}}
+			},
+		},
+		"inside map value": {
+			input: func() *testConfig {
+				return &testConfig{Nested: map[string]testServer{"x": {Host: "a", Port: 1}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Nested"), NewMapKey("x"), NewInstanceVariableNamed("Host"))
+			},
+			expected: func() *testConfig {
+				return &testConfig{Nested: map[string]testServer{"x": {Port: 1}}}
+			},
+		},
+		"root": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return NewRoot()
+			},
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		actual := c.input()
+		err := Delete(actual, c.path())
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+		} else {
+			require.NoError(t, err, caseName)
+			assert.Equal(t, c.expected(), actual, caseName)
+		}
+	}
+}
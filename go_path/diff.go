@@ -0,0 +1,236 @@
+package go_path
+
+import (
+	"reflect"
+)
+
+// ChangeOp is the kind of change found between two values
+type ChangeOp uint8
+
+const (
+	ChangeOpInvalid ChangeOp = iota
+	// ChangeOpAdded the path only exists in the new value
+	ChangeOpAdded
+	// ChangeOpRemoved the path only exists in the old value
+	ChangeOpRemoved
+	// ChangeOpModified the path exists in both values, but the values differ
+	ChangeOpModified
+	// ChangeOpMoved a slice element was moved from one index to another, only reported with DiffAlignSlices
+	ChangeOpMoved
+)
+
+func (o ChangeOp) String() string {
+	switch o {
+	case ChangeOpAdded:
+		return "added"
+	case ChangeOpRemoved:
+		return "removed"
+	case ChangeOpModified:
+		return "modified"
+	case ChangeOpMoved:
+		return "moved"
+	default:
+		return "invalid"
+	}
+}
+
+// Change is a single difference between two values
+type Change struct {
+	// Path to the value that changed
+	Path Pather
+	// Op is how the value changed
+	Op ChangeOp
+	// Old value, nil when the value was added
+	Old interface{}
+	// New value, nil when the value was removed
+	New interface{}
+	// From is the path the value was moved from, only set when Op is ChangeOpMoved
+	From Pather
+}
+
+// DiffOption configures how Diff compares values
+type DiffOption func(*diffOptions)
+
+type diffOptions struct {
+	alignSlices bool
+}
+
+// DiffAlignSlices aligns slice elements using their longest common subsequence, instead of comparing them index by
+// index. Inserting or removing an element then only reports that element rather than every element after it, and
+// elements that changed position are reported as moved. Removed elements are reported at their index in the old value,
+// all other changes at their index in the new value. Elements are equal when Diff would find no changes between them.
+// Slices that differ by more than 512 removed and added elements are still compared index by index.
+func DiffAlignSlices() DiffOption {
+	return func(o *diffOptions) {
+		o.alignSlices = true
+	}
+}
+
+// Diff compares a with b and reports every location where they differ.
+// Structs are compared field by field, slices and arrays index by index and maps key by key, so that changes are
+// reported at the deepest path that differs. Pointers and interfaces are dereferenced. Nil and empty slices or maps
+// are considered equal. Un-exported struct fields are not compared. Changes are reported in a deterministic order.
+func Diff(a, b interface{}, opts ...DiffOption) []Change {
+	d := differ{
+		changes: make([]Change, 0),
+		visited: make(map[[2]pointerKey]bool),
+	}
+	for _, opt := range opts {
+		opt(&d.options)
+	}
+	d.diff(reflect.ValueOf(a), reflect.ValueOf(b), NewRoot())
+	return d.changes
+}
+
+type differ struct {
+	options diffOptions
+	changes []Change
+	// visited are the pairs of pointers, maps and slices already compared, to avoid looping forever over cycles
+	visited map[[2]pointerKey]bool
+	// stopAtChanges stops comparing once a change is found, when only whether the values are equal matters
+	stopAtChanges bool
+}
+
+// compared is true if a and b are the same pointer, map or slice, or were already compared. Otherwise they are
+// recorded as compared
+func (d *differ) compared(a, b reflect.Value) bool {
+	if !isReference(a) || !isReference(b) {
+		return false
+	}
+	pair := [2]pointerKey{newPointerKey(a), newPointerKey(b)}
+	if pair[0] == pair[1] || d.visited[pair] {
+		return true
+	}
+	d.visited[pair] = true
+	return false
+}
+
+func (d *differ) add(p PathMutator, op ChangeOp, old, new reflect.Value) {
+	d.changes = append(d.changes, Change{
+		Path: p.Copy(),
+		Op:   op,
+		Old:  interfaceOrNil(old),
+		New:  interfaceOrNil(new),
+	})
+}
+
+func (d *differ) diff(a, b reflect.Value, p PathMutator) {
+	if d.stopAtChanges && len(d.changes) != 0 {
+		return
+	}
+	a, b = stripInterfaces(a), stripInterfaces(b)
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			d.add(p, ChangeOpModified, a, b)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		d.add(p, ChangeOpModified, a, b)
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.add(p, ChangeOpModified, a, b)
+			}
+			return
+		}
+		if d.compared(a, b) {
+			return
+		}
+		d.diff(a.Elem(), b.Elem(), p)
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			p.Append(NewInstanceVariableNamed(field.Name))
+			d.diff(a.Field(i), b.Field(i), p)
+			p.Pop(1)
+		}
+	case reflect.Slice:
+		if d.compared(a, b) {
+			return
+		}
+		if d.options.alignSlices {
+			d.diffAligned(a, b, p)
+		} else {
+			d.diffIndexes(a, b, p)
+		}
+	case reflect.Array:
+		d.diffIndexes(a, b, p)
+	case reflect.Map:
+		if d.compared(a, b) {
+			return
+		}
+		d.diffMaps(a, b, p)
+	default:
+		if !reflect.DeepEqual(interfaceOrNil(a), interfaceOrNil(b)) {
+			d.add(p, ChangeOpModified, a, b)
+		}
+	}
+}
+
+// diffIndexes compares slices or arrays index by index
+func (d *differ) diffIndexes(a, b reflect.Value, p PathMutator) {
+	common := minInt(a.Len(), b.Len())
+	for i := 0; i < common; i++ {
+		p.Append(NewArrayIndex(i))
+		d.diff(a.Index(i), b.Index(i), p)
+		p.Pop(1)
+	}
+	for i := common; i < a.Len(); i++ {
+		p.Append(NewArrayIndex(i))
+		d.add(p, ChangeOpRemoved, a.Index(i), reflect.Value{})
+		p.Pop(1)
+	}
+	for i := common; i < b.Len(); i++ {
+		p.Append(NewArrayIndex(i))
+		d.add(p, ChangeOpAdded, reflect.Value{}, b.Index(i))
+		p.Pop(1)
+	}
+}
+
+// diffMaps compares maps by their keys
+func (d *differ) diffMaps(a, b reflect.Value, p PathMutator) {
+	keys := a.MapKeys()
+	for _, key := range b.MapKeys() {
+		if !a.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+	sortMapKeys(keys)
+	for _, key := range keys {
+		aValue, bValue := a.MapIndex(key), b.MapIndex(key)
+		p.Append(mapKeyComponent(key))
+		switch {
+		case !bValue.IsValid():
+			d.add(p, ChangeOpRemoved, aValue, bValue)
+		case !aValue.IsValid():
+			d.add(p, ChangeOpAdded, aValue, bValue)
+		default:
+			d.diff(aValue, bValue, p)
+		}
+		p.Pop(1)
+	}
+}
+
+// stripInterfaces returns the value stored in an interface, or v if it is not an interface
+func stripInterfaces(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	return v
+}
+
+// interfaceOrNil returns the value as an interface{}, or nil if that is not possible
+func interfaceOrNil(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
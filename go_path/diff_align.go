@@ -0,0 +1,212 @@
+package go_path
+
+import (
+	"reflect"
+)
+
+// maxAlignedEdits is the most elements that may be removed and added between two slices for them to be aligned. Beyond
+// it, aligning and looking for moves would take time proportional to its square, so the slices are compared index by
+// index instead
+const maxAlignedEdits = 512
+
+// diffAligned compares slices by first aligning their elements on the longest common subsequence. Elements outside of
+// that subsequence that are equal to one another are moves. Remaining elements between the same two aligned elements
+// are paired up and compared as modifications, any left over were removed or added.
+func (d *differ) diffAligned(a, b reflect.Value, p PathMutator) {
+	anchors, ok := d.longestCommonSubsequence(a, b)
+	if !ok {
+		d.diffIndexes(a, b, p)
+		return
+	}
+
+	// unmatched elements in b that are equal to an unmatched element in a were moved
+	movedFrom := make(map[int]int)
+	isMoved := make(map[int]bool)
+	removed := unanchored(anchors, a.Len(), func(anchor [2]int) int { return anchor[0] })
+	added := unanchored(anchors, b.Len(), func(anchor [2]int) int { return anchor[1] })
+	for _, i := range removed {
+		for _, j := range added {
+			if _, taken := movedFrom[j]; !taken && d.equal(a.Index(i), b.Index(j)) {
+				movedFrom[j] = i
+				isMoved[i] = true
+				break
+			}
+		}
+	}
+
+	previous := [2]int{-1, -1}
+	gaps := append(anchors, [2]int{a.Len(), b.Len()})
+	for _, next := range gaps {
+		gapRemoved := make([]int, 0)
+		for i := previous[0] + 1; i < next[0]; i++ {
+			if !isMoved[i] {
+				gapRemoved = append(gapRemoved, i)
+			}
+		}
+		gapAdded := make([]int, 0)
+		for j := previous[1] + 1; j < next[1]; j++ {
+			if i, ok := movedFrom[j]; ok {
+				from := p.Copy()
+				from.Append(NewArrayIndex(i))
+				p.Append(NewArrayIndex(j))
+				d.changes = append(d.changes, Change{
+					Path: p.Copy(),
+					Op:   ChangeOpMoved,
+					Old:  interfaceOrNil(a.Index(i)),
+					New:  interfaceOrNil(b.Index(j)),
+					From: from,
+				})
+				p.Pop(1)
+			} else {
+				gapAdded = append(gapAdded, j)
+			}
+		}
+
+		paired := minInt(len(gapRemoved), len(gapAdded))
+		for k := 0; k < paired; k++ {
+			p.Append(NewArrayIndex(gapAdded[k]))
+			d.diff(a.Index(gapRemoved[k]), b.Index(gapAdded[k]), p)
+			p.Pop(1)
+		}
+		for _, i := range gapRemoved[paired:] {
+			p.Append(NewArrayIndex(i))
+			d.add(p, ChangeOpRemoved, a.Index(i), reflect.Value{})
+			p.Pop(1)
+		}
+		for _, j := range gapAdded[paired:] {
+			p.Append(NewArrayIndex(j))
+			d.add(p, ChangeOpAdded, reflect.Value{}, b.Index(j))
+			p.Pop(1)
+		}
+		previous = next
+	}
+}
+
+// longestCommonSubsequence returns the pairs of indexes, in increasing order, of equal elements in a and b that form
+// the longest common subsequence of the two slices. It uses Myers' algorithm on what remains once the common prefix and
+// suffix are removed, which takes time proportional to the length of the slices times the number of elements removed
+// and added. It is not ok if more than maxAlignedEdits elements were removed and added
+func (d *differ) longestCommonSubsequence(a, b reflect.Value) ([][2]int, bool) {
+	n, m := a.Len(), b.Len()
+	prefix := 0
+	for prefix < n && prefix < m && d.equal(a.Index(prefix), b.Index(prefix)) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && d.equal(a.Index(n-1-suffix), b.Index(m-1-suffix)) {
+		suffix++
+	}
+
+	middle, ok := d.shortestEditAnchors(a.Slice(prefix, n-suffix), b.Slice(prefix, m-suffix))
+	if !ok {
+		return nil, false
+	}
+	anchors := make([][2]int, 0, prefix+len(middle)+suffix)
+	for i := 0; i < prefix; i++ {
+		anchors = append(anchors, [2]int{i, i})
+	}
+	for _, anchor := range middle {
+		anchors = append(anchors, [2]int{anchor[0] + prefix, anchor[1] + prefix})
+	}
+	for i := suffix; i > 0; i-- {
+		anchors = append(anchors, [2]int{n - i, m - i})
+	}
+	return anchors, true
+}
+
+// shortestEditAnchors finds the fewest elements to remove from a and add to b to turn one into the other, using Myers'
+// algorithm, and returns the pairs of equal elements that are kept, in increasing order. It is not ok if more than
+// maxAlignedEdits elements would need to be removed and added
+func (d *differ) shortestEditAnchors(a, b reflect.Value) ([][2]int, bool) {
+	n, m := a.Len(), b.Len()
+	limit := minInt(n+m, maxAlignedEdits)
+	// furthest[offset+k] is the furthest index reached in a along diagonal k, where k is the index in a minus the index
+	// in b. trace records furthest before each number of edits, to walk the path back
+	offset := limit + 1
+	furthest := make([]int, 2*limit+3)
+	trace := make([][]int, 0)
+	for edits := 0; edits <= limit; edits++ {
+		trace = append(trace, append([]int(nil), furthest...))
+		for k := -edits; k <= edits; k += 2 {
+			var i int
+			if k == -edits || (k != edits && furthest[offset+k-1] < furthest[offset+k+1]) {
+				i = furthest[offset+k+1]
+			} else {
+				i = furthest[offset+k-1] + 1
+			}
+			j := i - k
+			for i < n && j < m && d.equal(a.Index(i), b.Index(j)) {
+				i++
+				j++
+			}
+			furthest[offset+k] = i
+			if i >= n && j >= m {
+				return editAnchors(trace, offset, n, m), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// editAnchors walks back through the trace of shortestEditAnchors from the ends of both slices, collecting the equal
+// elements that were stepped over diagonally
+func editAnchors(trace [][]int, offset, n, m int) [][2]int {
+	anchors := make([][2]int, 0)
+	i, j := n, m
+	for edits := len(trace) - 1; edits >= 0; edits-- {
+		furthest := trace[edits]
+		k := i - j
+		var previousK int
+		if k == -edits || (k != edits && furthest[offset+k-1] < furthest[offset+k+1]) {
+			previousK = k + 1
+		} else {
+			previousK = k - 1
+		}
+		previousI := furthest[offset+previousK]
+		previousJ := previousI - previousK
+		for i > previousI && j > previousJ {
+			i--
+			j--
+			anchors = append(anchors, [2]int{i, j})
+		}
+		i, j = previousI, previousJ
+	}
+	for left, right := 0, len(anchors)-1; left < right; left, right = left+1, right-1 {
+		anchors[left], anchors[right] = anchors[right], anchors[left]
+	}
+	return anchors
+}
+
+// equal is true if diffing a and b finds no changes, so that slice elements are aligned by the same rules Diff uses
+func (d *differ) equal(a, b reflect.Value) bool {
+	e := differ{
+		changes:       make([]Change, 0),
+		visited:       make(map[[2]pointerKey]bool),
+		stopAtChanges: true,
+	}
+	e.diff(a, b, NewRoot())
+	return len(e.changes) == 0
+}
+
+// unanchored lists the indexes below length that are not part of any anchor
+func unanchored(anchors [][2]int, length int, side func(anchor [2]int) int) []int {
+	anchored := make(map[int]bool, len(anchors))
+	for _, anchor := range anchors {
+		anchored[side(anchor)] = true
+	}
+	out := make([]int, 0, length-len(anchors))
+	for i := 0; i < length; i++ {
+		if !anchored[i] {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// valuesEqual is true if both values are deeply equal
+func valuesEqual(a, b reflect.Value) bool {
+	if !a.CanInterface() || !b.CanInterface() {
+		return false
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -0,0 +1,290 @@
+package go_path
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+type testDiffSecret struct {
+	Name   string
+	secret int
+}
+
+type testDiffChange struct {
+	path string
+	op   ChangeOp
+	old  interface{}
+	new  interface{}
+}
+
+func changesToTest(changes []Change) []testDiffChange {
+	out := make([]testDiffChange, len(changes))
+	for i, change := range changes {
+		out[i] = testDiffChange{
+			path: change.Path.String(),
+			op:   change.Op,
+			old:  change.Old,
+			new:  change.New,
+		}
+	}
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	cases := map[string]struct {
+		a        interface{}
+		b        interface{}
+		expected []testDiffChange
+	}{
+		"equal": {
+			a:        &testConfig{Servers: []testServer{{Host: "a"}}},
+			b:        &testConfig{Servers: []testServer{{Host: "a"}}},
+			expected: []testDiffChange{},
+		},
+		"nil and empty": {
+			a:        &testConfig{Labels: map[string]string{}},
+			b:        &testConfig{Servers: []testServer{}},
+			expected: []testDiffChange{},
+		},
+		"modified field": {
+			a: &testConfig{Servers: []testServer{{Host: "a", Port: 1}}},
+			b: &testConfig{Servers: []testServer{{Host: "b", Port: 1}}},
+			expected: []testDiffChange{
+				{path: "Servers[0].Host", op: ChangeOpModified, old: "a", new: "b"},
+			},
+		},
+		"slice grew and shrank": {
+			a: []int{1, 2, 3},
+			b: []int{1, 5},
+			expected: []testDiffChange{
+				{path: "[1]", op: ChangeOpModified, old: 2, new: 5},
+				{path: "[2]", op: ChangeOpRemoved, old: 3},
+			},
+		},
+		"slice added": {
+			a: []int{1},
+			b: []int{1, 2},
+			expected: []testDiffChange{
+				{path: "[1]", op: ChangeOpAdded, new: 2},
+			},
+		},
+		"maps": {
+			a: map[string]int{"a": 1, "b": 2, "c": 3},
+			b: map[string]int{"b": 2, "c": 4, "d": 5},
+			expected: []testDiffChange{
+				{path: "[\"a\"]", op: ChangeOpRemoved, old: 1},
+				{path: "[\"c\"]", op: ChangeOpModified, old: 3, new: 4},
+				{path: "[\"d\"]", op: ChangeOpAdded, new: 5},
+			},
+		},
+		"pointer set": {
+			a: &testServer{},
+			b: &testServer{TLS: &testTLS{Cert: "x"}},
+			expected: []testDiffChange{
+				{path: "TLS", op: ChangeOpModified, old: (*testTLS)(nil), new: &testTLS{Cert: "x"}},
+			},
+		},
+		"pointer contents": {
+			a: &testServer{TLS: &testTLS{Cert: "x"}},
+			b: &testServer{TLS: &testTLS{Cert: "y"}},
+			expected: []testDiffChange{
+				{path: "TLS.Cert", op: ChangeOpModified, old: "x", new: "y"},
+			},
+		},
+		"interface types differ": {
+			a: map[string]interface{}{"a": 1},
+			b: map[string]interface{}{"a": "1"},
+			expected: []testDiffChange{
+				{path: "[\"a\"]", op: ChangeOpModified, old: 1, new: "1"},
+			},
+		},
+		"cycles": {
+			a: newTestList(),
+			b: func() *testNode {
+				l := newTestList()
+				l.Next.Name = "other"
+				return l
+			}(),
+			expected: []testDiffChange{
+				{path: "Next.Name", op: ChangeOpModified, old: "second", new: "other"},
+			},
+		},
+		"maps containing themselves": {
+			a: func() interface{} {
+				m := map[string]interface{}{"n": 1}
+				m["self"] = m
+				return m
+			}(),
+			b: func() interface{} {
+				m := map[string]interface{}{"n": 2}
+				m["self"] = m
+				return m
+			}(),
+			expected: []testDiffChange{
+				{path: `["n"]`, op: ChangeOpModified, old: 1, new: 2},
+			},
+		},
+		"slices containing themselves": {
+			a: func() interface{} {
+				s := []interface{}{"a", nil}
+				s[1] = s
+				return s
+			}(),
+			b: func() interface{} {
+				s := []interface{}{"b", nil}
+				s[1] = s
+				return s
+			}(),
+			expected: []testDiffChange{
+				{path: "[0]", op: ChangeOpModified, old: "a", new: "b"},
+			},
+		},
+	}
+
+	for caseName, c := range cases {
+		actual := changesToTest(Diff(c.a, c.b))
+		assert.Equal(t, c.expected, actual, caseName)
+	}
+}
+
+func TestDiff_AlignSlices(t *testing.T) {
+	cases := map[string]struct {
+		a        interface{}
+		b        interface{}
+		expected []testDiffChange
+	}{
+		"equal": {
+			a:        []string{"a", "b"},
+			b:        []string{"a", "b"},
+			expected: []testDiffChange{},
+		},
+		"insert at front": {
+			a: []string{"a", "b", "c"},
+			b: []string{"z", "a", "b", "c"},
+			expected: []testDiffChange{
+				{path: "[0]", op: ChangeOpAdded, new: "z"},
+			},
+		},
+		"remove from middle": {
+			a: []string{"a", "b", "c"},
+			b: []string{"a", "c"},
+			expected: []testDiffChange{
+				{path: "[1]", op: ChangeOpRemoved, old: "b"},
+			},
+		},
+		"modify in place": {
+			a: []testServer{{Host: "a"}, {Host: "b", Port: 1}, {Host: "c"}},
+			b: []testServer{{Host: "a"}, {Host: "b", Port: 2}, {Host: "c"}},
+			expected: []testDiffChange{
+				{path: "[1].Port", op: ChangeOpModified, old: 1, new: 2},
+			},
+		},
+		"nested in struct": {
+			a: &testConfig{Servers: []testServer{{Host: "a"}, {Host: "b"}}},
+			b: &testConfig{Servers: []testServer{{Host: "b"}}},
+			expected: []testDiffChange{
+				{path: "Servers[0]", op: ChangeOpRemoved, old: testServer{Host: "a"}},
+			},
+		},
+		"un-exported fields are not compared": {
+			a: []testDiffSecret{{Name: "a", secret: 1}, {Name: "b", secret: 2}},
+			b: []testDiffSecret{{Name: "z"}, {Name: "a", secret: 3}, {Name: "b", secret: 4}},
+			expected: []testDiffChange{
+				{path: "[0]", op: ChangeOpAdded, new: testDiffSecret{Name: "z"}},
+			},
+		},
+		"nil and empty elements are equal": {
+			a: [][]string{nil, {"x"}},
+			b: [][]string{{"z"}, {}, {"x"}},
+			expected: []testDiffChange{
+				{path: "[0]", op: ChangeOpAdded, new: []string{"z"}},
+			},
+		},
+	}
+
+	for caseName, c := range cases {
+		actual := changesToTest(Diff(c.a, c.b, DiffAlignSlices()))
+		assert.Equal(t, c.expected, actual, caseName)
+	}
+}
+
+func TestDiff_AlignSlicesMoved(t *testing.T) {
+	changes := Diff([]string{"a", "b", "c", "d", "e", "f"}, []string{"a", "b", "d", "e", "f", "c"}, DiffAlignSlices())
+	require.Len(t, changes, 1)
+	assert.Equal(t, ChangeOpMoved, changes[0].Op)
+	assert.Equal(t, "[5]", changes[0].Path.String())
+	assert.Equal(t, "[2]", changes[0].From.String())
+	assert.Equal(t, "c", changes[0].New)
+}
+
+func TestDiff_AlignSlicesLarge(t *testing.T) {
+	a := make([]int, 100000)
+	for i := range a {
+		a[i] = i
+	}
+	b := append([]int{-1}, a...)
+	b[50000] = -2
+	assert.Equal(t, []testDiffChange{
+		{path: "[0]", op: ChangeOpAdded, new: -1},
+		{path: "[50000]", op: ChangeOpModified, old: 49999, new: -2},
+	}, changesToTest(Diff(a, b, DiffAlignSlices())))
+
+	// too many differences to align, so they are compared index by index
+	c := make([]int, 2*maxAlignedEdits)
+	for i := range c {
+		c[i] = -i - 1
+	}
+	changes := Diff(a[:len(c)], c, DiffAlignSlices())
+	require.Len(t, changes, len(c))
+	for i, change := range changes {
+		assert.Equal(t, ChangeOpModified, change.Op)
+		assert.Equal(t, NewArrayIndex(i).String(), change.Path.String())
+	}
+}
+
+func TestDiffer_LongestCommonSubsequence(t *testing.T) {
+	property := func(a, b []uint8) bool {
+		// few distinct elements, so that there is something in common
+		for i := range a {
+			a[i] %= 4
+		}
+		for i := range b {
+			b[i] %= 4
+		}
+		d := differ{}
+		anchors, ok := d.longestCommonSubsequence(reflect.ValueOf(a), reflect.ValueOf(b))
+		if !ok {
+			return false
+		}
+		previous := [2]int{-1, -1}
+		for _, anchor := range anchors {
+			if anchor[0] <= previous[0] || anchor[1] <= previous[1] || a[anchor[0]] != b[anchor[1]] {
+				return false
+			}
+			previous = anchor
+		}
+		return len(anchors) == testLongestCommonSubsequenceLength(a, b)
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+// testLongestCommonSubsequenceLength finds the length of the longest common subsequence with a table
+func testLongestCommonSubsequenceLength(a, b []uint8) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = maxInt(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	return lengths[0][0]
+}
@@ -0,0 +1,143 @@
+package go_path
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// editFunc modifies container, the settable and indirected parent of the last component in a path
+type editFunc func(container reflect.Value, last Componenter, resolved PathMutator) error
+
+// settableRoot returns the settable value pointed at by root
+func settableRoot(root interface{}) (reflect.Value, error) {
+	rootValue := reflect.ValueOf(root)
+	if rootValue.Kind() != reflect.Ptr || rootValue.IsNil() {
+		return reflect.Value{}, fmt.Errorf("root must be a non-nil pointer, but got %T", root)
+	}
+	return rootValue.Elem(), nil
+}
+
+// editAt descends from target, which must be settable, through all but the last of parts and calls edit with the
+// container holding the last part. Values that are not addressable, such as map elements and values inside
+// interfaces, are copied, edited and then written back into their containers.
+func editAt(target reflect.Value, parts []Componenter, resolved PathMutator, o *setOptions, edit editFunc) error {
+	switch target.Kind() {
+	case reflect.Ptr:
+		if target.IsNil() {
+			if !o.allocatePointers {
+				return newResolveError(ResolveReasonNilPointer, target).locate(resolved, parts[0])
+			}
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return editAt(target.Elem(), parts, resolved, o, edit)
+	case reflect.Interface:
+		if target.IsNil() {
+			return newResolveError(ResolveReasonNilPointer, target).locate(resolved, parts[0])
+		}
+		elem := target.Elem()
+		editable := reflect.New(elem.Type()).Elem()
+		editable.Set(elem)
+		if err := editAt(editable, parts, resolved, o, edit); err != nil {
+			return err
+		}
+		target.Set(editable)
+		return nil
+	}
+
+	if len(parts) == 1 {
+		return edit(target, parts[0], resolved)
+	}
+	if embedded, ok := unexportedEmbeddedStruct(target, parts[0]); ok {
+		resolved.Append(parts[0])
+		return editAt(embedded, parts[1:], resolved, o, edit)
+	}
+	return descend(target, parts[0], resolved, o, o.createMaps, func(child reflect.Value) error {
+		return editAt(child, parts[1:], resolved, o, edit)
+	})
+}
+
+// unexportedEmbeddedStruct returns the un-exported embedded struct of target identified by component. It cannot be set,
+// but its exported fields, which are promoted, can be. ok is false for any other component
+func unexportedEmbeddedStruct(target reflect.Value, component Componenter) (embedded reflect.Value, ok bool) {
+	c, isField := component.(*pathStructInstanceVariable)
+	if !isField || target.Kind() != reflect.Struct || !target.CanAddr() {
+		return reflect.Value{}, false
+	}
+	field, found := target.Type().FieldByName(c.variableName)
+	if !found || !field.Anonymous || field.PkgPath == "" || field.Type.Kind() != reflect.Struct || len(field.Index) != 1 {
+		return reflect.Value{}, false
+	}
+	return target.Field(field.Index[0]), true
+}
+
+// descend locates the settable child of target identified by component, appends component to resolved and calls next
+// with that child. createMissingKey permits descending into map keys that do not exist yet.
+func descend(target reflect.Value, component Componenter, resolved PathMutator, o *setOptions, createMissingKey bool, next func(child reflect.Value) error) error {
+	switch c := component.(type) {
+	case *pathStructInstanceVariable, *pathKeyedElement:
+		field, err := settableChild(target, c, resolved)
+		if err != nil {
+			return err
+		}
+		resolved.Append(c)
+		return next(field)
+	case *pathArrayInstanceVariable:
+		if target.Kind() == reflect.Slice && c.index >= target.Len() && o.growSlices {
+			// comparing the index, rather than the length it needs, cannot overflow
+			if c.index >= o.maxGrownLen {
+				e := newResolveError(ResolveReasonIndexOutOfRange, target)
+				e.Len = target.Len()
+				return e.locate(resolved, c)
+			}
+			grown := reflect.MakeSlice(target.Type(), c.index+1, maxInt(c.index+1, target.Cap()))
+			reflect.Copy(grown, target)
+			target.Set(grown)
+		}
+		elem, err := settableChild(target, c, resolved)
+		if err != nil {
+			return err
+		}
+		resolved.Append(c)
+		return next(elem)
+	case mapKeyer:
+		if target.Kind() != reflect.Map {
+			return newResolveError(ResolveReasonKindMismatch, target).locate(resolved, c)
+		}
+		key, ok := c.keyFor(target.Type().Key())
+		if !ok {
+			return newResolveError(ResolveReasonKindMismatch, target).locate(resolved, c)
+		}
+		if target.IsNil() {
+			if !o.createMaps {
+				return newResolveError(ResolveReasonNilPointer, target).locate(resolved, c)
+			}
+			target.Set(reflect.MakeMap(target.Type()))
+		}
+		editable := reflect.New(target.Type().Elem()).Elem()
+		if existing := target.MapIndex(key); existing.IsValid() {
+			editable.Set(existing)
+		} else if !createMissingKey {
+			return newResolveError(ResolveReasonMissingMapKey, target).locate(resolved, c)
+		}
+		resolved.Append(c)
+		if err := next(editable); err != nil {
+			return err
+		}
+		target.SetMapIndex(key, editable)
+		return nil
+	default:
+		return newResolveError(ResolveReasonUnsupportedComponent, target).locate(resolved, component)
+	}
+}
+
+// settableChild resolves component against target and ensures the result may be modified
+func settableChild(target reflect.Value, component Componenter, resolved Pather) (reflect.Value, error) {
+	child, resolveErr := resolveComponent(target, component)
+	if resolveErr != nil {
+		return reflect.Value{}, resolveErr.locate(resolved, component)
+	}
+	if !child.CanSet() {
+		return reflect.Value{}, newResolveError(ResolveReasonNotSettable, target).locate(resolved, component)
+	}
+	return child, nil
+}
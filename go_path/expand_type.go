@@ -0,0 +1,120 @@
+package go_path
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ExpandType returns the concrete paths that p could match in any value of type t, without a value.
+// Unions expand into each of their alternatives, and wildcards over structs expand into every exported field.
+// Components whose matches depend on the value, such as wildcards over slices and maps, filters and descendants, are
+// an error; ranges are only expanded over arrays, as their length is part of the type. Expansion stops at interface
+// types, past which the rest of p must already be concrete. Paths that cannot exist in t are not returned
+func ExpandType(t reflect.Type, p Pather) ([]Pather, error) {
+	e := typeExpander{
+		pattern:  p,
+		parts:    components(p),
+		expanded: make([]Pather, 0),
+	}
+	if err := e.expand(t, 0, NewRoot()); err != nil {
+		return nil, err
+	}
+	return e.expanded, nil
+}
+
+type typeExpander struct {
+	pattern  Pather
+	parts    []Componenter
+	expanded []Pather
+}
+
+// expand the parts from index onward through the type t, found at resolved
+func (e *typeExpander) expand(t reflect.Type, index int, resolved PathMutator) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if index == len(e.parts) {
+		e.expanded = append(e.expanded, resolved.Copy())
+		return nil
+	}
+	if t.Kind() == reflect.Interface {
+		rest := New(e.parts[index:]...)
+		if !IsConcrete(rest) {
+			return fmt.Errorf("unable to expand \"%s\": \"%s\" is beneath an interface", e.pattern.String(), rest.String())
+		}
+		found := resolved.Copy()
+		found.Append(e.parts[index:]...)
+		e.expanded = append(e.expanded, found)
+		return nil
+	}
+	var alternatives []Componenter
+	switch c := e.parts[index].(type) {
+	case *pathUnion:
+		alternatives = c.alternatives
+	case *pathWildcard:
+		if t.Kind() != reflect.Struct {
+			return e.dependsOnValue(c, t)
+		}
+		alternatives = make([]Componenter, 0, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			if field := t.Field(i); field.PkgPath == "" {
+				alternatives = append(alternatives, NewInstanceVariableNamed(field.Name))
+			}
+		}
+	case *pathArrayRange:
+		if t.Kind() != reflect.Array {
+			return e.dependsOnValue(c, t)
+		}
+		for _, i := range c.Indexes(t.Len()) {
+			alternatives = append(alternatives, NewArrayIndex(i))
+		}
+	case expander:
+		return e.dependsOnValue(c, t)
+	default:
+		alternatives = []Componenter{c}
+	}
+	for _, alternative := range alternatives {
+		child, ok := childType(t, alternative)
+		if !ok {
+			continue
+		}
+		resolved.Append(alternative)
+		if err := e.expand(child, index+1, resolved); err != nil {
+			return err
+		}
+		resolved.Pop(1)
+	}
+	return nil
+}
+
+func (e *typeExpander) dependsOnValue(component Componenter, t reflect.Type) error {
+	return fmt.Errorf("unable to expand \"%s\": %s depends on the value of %s", e.pattern.String(), component.String(), t.String())
+}
+
+// childType is the type of the child of t that the concrete component locates. ok is false if t has no such child
+func childType(t reflect.Type, component Componenter) (child reflect.Type, ok bool) {
+	switch c := component.(type) {
+	case *pathStructInstanceVariable:
+		if t.Kind() != reflect.Struct {
+			return nil, false
+		}
+		field, ok := t.FieldByName(c.variableName)
+		return field.Type, ok
+	case *pathArrayInstanceVariable:
+		if t.Kind() == reflect.Array {
+			_, ok = c.absoluteIndex(t.Len())
+			return t.Elem(), ok
+		}
+		return t.Elem(), t.Kind() == reflect.Slice
+	case *pathKeyedElement:
+		return t.Elem(), t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+	case mapKeyer:
+		if t.Kind() != reflect.Map {
+			return nil, false
+		}
+		_, ok = c.keyFor(t.Key())
+		return t.Elem(), ok
+	default:
+		return nil, false
+	}
+}
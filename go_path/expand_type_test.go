@@ -0,0 +1,74 @@
+package go_path
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+func TestExpandType(t *testing.T) {
+	cases := map[string]struct {
+		path        string
+		expected    []string
+		expectedErr bool
+	}{
+		"concrete": {
+			path:     "Dogs[0].Name",
+			expected: []string{"Dogs[0].Name"},
+		},
+		"field union": {
+			path:     "Dogs[0].{Name,Owner,Missing}",
+			expected: []string{"Dogs[0].Name", "Dogs[0].Owner"},
+		},
+		"nested unions": {
+			path:     `Dogs[0,1].Attributes["fur","skin"].Color`,
+			expected: []string{`Dogs[0].Attributes["fur"].Color`, `Dogs[0].Attributes["skin"].Color`, `Dogs[1].Attributes["fur"].Color`, `Dogs[1].Attributes["skin"].Color`},
+		},
+		"struct wildcard through pointer": {
+			path:     "Dogs[0].Owner.*",
+			expected: []string{"Dogs[0].Owner.Name"},
+		},
+		"array range": {
+			path:     "Counts[::-1]",
+			expected: []string{"Counts[1]", "Counts[0]"},
+		},
+		"array index out of range": {
+			path:     "Counts[0,2]",
+			expected: []string{"Counts[0]"},
+		},
+		"beneath interface": {
+			path:     `Dogs[0].Extra["legs"]`,
+			expected: []string{`Dogs[0].Extra["legs"]`},
+		},
+		"pattern beneath interface": {
+			path:        `Dogs[0].Extra[*]`,
+			expectedErr: true,
+		},
+		"slice wildcard": {
+			path:        "Dogs[*].Name",
+			expectedErr: true,
+		},
+		"filter": {
+			path:        "Dogs[?(@.Name)]",
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		p, err := Parse(bytes.NewBufferString(c.path))
+		require.NoError(t, err, caseName)
+		expanded, err := ExpandType(reflect.TypeOf(&testKennel{}), p)
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+			continue
+		}
+		require.NoError(t, err, caseName)
+		actual := make([]string, len(expanded))
+		for i, concrete := range expanded {
+			actual[i] = concrete.String()
+		}
+		assert.Equal(t, c.expected, actual, caseName)
+	}
+}
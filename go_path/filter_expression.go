@@ -0,0 +1,477 @@
+package go_path
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter expressions are evaluated against each element selected by a filter component.
+// Grammar, from lowest to highest precedence:
+// * or:         and ( "||" and )*
+// * and:        not ( "&&" not )*
+// * not:        "!" not | primary
+// * primary:    "(" or ")" | operand ( comparison operand )?
+// * comparison: "==" | "!=" | "<" | "<=" | ">" | ">="
+// * operand:    "@" path | number | "string" | true | false | null
+// @ is the element itself. It may be followed by a concrete path relative to the element: @.age, @["key"], @[0].name
+// An operand that is not compared tests that the path exists: it resolves and is not a nil pointer, interface, map
+// or slice.
+
+// filterExpression is a node of a parsed filter expression
+type filterExpression interface {
+	evaluate(element reflect.Value) bool
+}
+
+type filterOr struct {
+	left, right filterExpression
+}
+
+func (f filterOr) evaluate(element reflect.Value) bool {
+	return f.left.evaluate(element) || f.right.evaluate(element)
+}
+
+type filterAnd struct {
+	left, right filterExpression
+}
+
+func (f filterAnd) evaluate(element reflect.Value) bool {
+	return f.left.evaluate(element) && f.right.evaluate(element)
+}
+
+type filterNot struct {
+	operand filterExpression
+}
+
+func (f filterNot) evaluate(element reflect.Value) bool {
+	return !f.operand.evaluate(element)
+}
+
+type filterExists struct {
+	operand filterOperand
+}
+
+func (f filterExists) evaluate(element reflect.Value) bool {
+	v, ok := f.operand.value(element)
+	return ok && v.IsValid()
+}
+
+// filterOperand is a value used in a filter expression
+type filterOperand interface {
+	// value of the operand for the element, dereferenced. The value is invalid for nil and null.
+	// ok is false if the operand's path does not resolve against element
+	value(element reflect.Value) (v reflect.Value, ok bool)
+}
+
+type filterPath struct {
+	path Pather
+}
+
+func (f filterPath) value(element reflect.Value) (reflect.Value, bool) {
+	v, err := resolve(element, f.path)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, true
+		}
+		v = v.Elem()
+	}
+	if (v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil() {
+		return reflect.Value{}, true
+	}
+	return v, true
+}
+
+type filterLiteral struct {
+	literal reflect.Value
+}
+
+func (f filterLiteral) value(_ reflect.Value) (reflect.Value, bool) {
+	return f.literal, true
+}
+
+type filterComparison struct {
+	left, right filterOperand
+	operator    string
+}
+
+func (f filterComparison) evaluate(element reflect.Value) bool {
+	left, ok := f.left.value(element)
+	if !ok {
+		return false
+	}
+	right, ok := f.right.value(element)
+	if !ok {
+		return false
+	}
+	order, comparable := compareFilterValues(left, right)
+	switch f.operator {
+	case "==":
+		return comparable && order == 0
+	case "!=":
+		return !comparable || order != 0
+	}
+	if !comparable || !isOrdered(left) {
+		return false
+	}
+	switch f.operator {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	default:
+		return order >= 0
+	}
+}
+
+// compareFilterValues returns -1, 0 or 1 as a is less than, equal to or greater than b.
+// comparable is false if the values cannot be compared, such as a string and a number.
+// Values that are equal but cannot be ordered, such as booleans, compare as 0
+func compareFilterValues(a, b reflect.Value) (order int, comparable bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return 0, !a.IsValid() && !b.IsValid()
+	}
+	switch {
+	case isIntegerKind(a.Kind()) && isIntegerKind(b.Kind()) && a.Kind() != reflect.Uint64 && b.Kind() != reflect.Uint64:
+		x, y := integerValue(a), integerValue(b)
+		return ordering(x < y, x > y), true
+	case isNumericKind(a.Kind()) && isNumericKind(b.Kind()):
+		x, y := floatValue(a), floatValue(b)
+		return ordering(x < y, x > y), true
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), true
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		if a.Bool() == b.Bool() {
+			return 0, true
+		}
+		return 1, true
+	case a.Type() == b.Type():
+		if valuesEqual(a, b) {
+			return 0, true
+		}
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+func ordering(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isOrdered(v reflect.Value) bool {
+	return isNumericKind(v.Kind()) || v.Kind() == reflect.String
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+// integerValue of an integer that fits in an int64
+func integerValue(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	default:
+		return int64(v.Uint())
+	}
+}
+
+func floatValue(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+// parseFilterExpression parses the source of a filter expression, as found between the parentheses of [?(...)].
+// Relative paths are parsed with options
+func parseFilterExpression(source string, options ParseOptions) (filterExpression, error) {
+	p := filterParser{source: []rune(source), options: options}
+	expression, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.atEnd() {
+		return nil, p.errorf("unexpected '%s'", string(p.source[p.position]))
+	}
+	return expression, nil
+}
+
+type filterParser struct {
+	source   []rune
+	position int
+	options  ParseOptions
+}
+
+// filterSyntaxError describes where a filter expression is invalid
+type filterSyntaxError struct {
+	// position of the problem, in runes from the start of the expression
+	position int
+	message  string
+	// err is the cause, such as the limit a relative path exceeded, if there is one
+	err error
+}
+
+func (e *filterSyntaxError) Error() string {
+	return fmt.Sprintf("invalid filter expression at offset %d: %s", e.position, e.message)
+}
+
+func (e *filterSyntaxError) Unwrap() error {
+	return e.err
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	return &filterSyntaxError{
+		position: p.position,
+		message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *filterParser) atEnd() bool {
+	return p.position >= len(p.source)
+}
+
+func (p *filterParser) skipSpace() {
+	for !p.atEnd() && unicode.IsSpace(p.source[p.position]) {
+		p.position++
+	}
+}
+
+// consume skips spaces and then symbol, returning false and consuming nothing if symbol is not next
+func (p *filterParser) consume(symbol string) bool {
+	p.skipSpace()
+	symbolRunes := []rune(symbol)
+	if len(p.source)-p.position < len(symbolRunes) {
+		return false
+	}
+	for i, r := range symbolRunes {
+		if p.source[p.position+i] != r {
+			return false
+		}
+	}
+	p.position += len(symbolRunes)
+	return true
+}
+
+func (p *filterParser) parseOr() (filterExpression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = filterOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("&&") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = filterAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (filterExpression, error) {
+	// != is a comparison, which never starts an expression
+	if p.consume("!") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *filterParser) parsePrimary() (filterExpression, error) {
+	if p.consume("(") {
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return expression, nil
+	}
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	// longer operators first, so that <= is not read as <
+	for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(operator) {
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return filterComparison{left: left, right: right, operator: operator}, nil
+		}
+	}
+	if _, ok := left.(filterPath); !ok {
+		return nil, p.errorf("a literal must be compared with something")
+	}
+	return filterExists{operand: left}, nil
+}
+
+func (p *filterParser) parseOperand() (filterOperand, error) {
+	p.skipSpace()
+	if p.atEnd() {
+		return nil, p.errorf("expected an operand")
+	}
+	r := p.source[p.position]
+	switch {
+	case '@' == r:
+		p.position++
+		return p.parseRelativePath()
+	case isQuoteDouble(r):
+		return p.parseString()
+	case '-' == r || isNumber(r):
+		return p.parseNumber()
+	case unicode.IsLetter(r):
+		start := p.position
+		for !p.atEnd() && isAlphaNumeric(p.source[p.position]) {
+			p.position++
+		}
+		switch word := string(p.source[start:p.position]); word {
+		case "true", "false":
+			return filterLiteral{literal: reflect.ValueOf(word == "true")}, nil
+		case "null", "nil":
+			return filterLiteral{}, nil
+		default:
+			p.position = start
+			return nil, p.errorf("unknown literal %s", word)
+		}
+	default:
+		return nil, p.errorf("expected an operand, found '%s'", string(r))
+	}
+}
+
+// parseRelativePath reads the path following @, which ends at the first space or operator outside of brackets and
+// quotes. Operators end lenient names as well
+func (p *filterParser) parseRelativePath() (filterOperand, error) {
+	start := p.position
+	depth := 0
+	// quote is the quote of the map key or field name being read, 0 outside of quotes
+	quote := rune(0)
+	isEscaped := false
+	for ; !p.atEnd(); p.position++ {
+		r := p.source[p.position]
+		if quote != 0 {
+			if !isEscaped && quote == r {
+				quote = 0
+			}
+			isEscaped = !isEscaped && isEscapeChar(r)
+			continue
+		}
+		if depth == 0 && !('[' == r || '.' == r || '*' == r || '\'' == r || (p.options.isNameRune(r) && !isFilterOperatorRune(r))) {
+			break
+		}
+		switch r {
+		case '"', '\'':
+			quote = r
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+	}
+	source := string(p.source[start:p.position])
+	if strings.HasPrefix(source, ".") && !strings.HasPrefix(source, "..") {
+		source = source[1:]
+	}
+	relative, err := ParseWithOptions(strings.NewReader(source), p.options)
+	if err != nil {
+		return nil, &filterSyntaxError{
+			position: p.position,
+			message:  fmt.Sprintf("invalid path @%s: %s", string(p.source[start:p.position]), err.Error()),
+			err:      err,
+		}
+	}
+	if !IsConcrete(relative) {
+		return nil, p.errorf("path @%s must only locate a single value", string(p.source[start:p.position]))
+	}
+	return filterPath{path: relative}, nil
+}
+
+// isFilterOperatorRune is true for the runes that start the operators of filter expressions
+func isFilterOperatorRune(r rune) bool {
+	return strings.ContainsRune("=!<>&|()", r)
+}
+
+func (p *filterParser) parseString() (filterOperand, error) {
+	start := p.position
+	isEscaped := false
+	for p.position++; !p.atEnd(); p.position++ {
+		r := p.source[p.position]
+		if !isEscaped && isQuoteDouble(r) {
+			p.position++
+			text, err := strconv.Unquote(string(p.source[start:p.position]))
+			if err != nil {
+				return nil, p.errorf("invalid string %s", string(p.source[start:p.position]))
+			}
+			return filterLiteral{literal: reflect.ValueOf(text)}, nil
+		}
+		isEscaped = !isEscaped && isEscapeChar(r)
+	}
+	return nil, p.errorf("unterminated string")
+}
+
+func (p *filterParser) parseNumber() (filterOperand, error) {
+	start := p.position
+	p.position++
+	for !p.atEnd() && strings.ContainsRune("0123456789.eE+-", p.source[p.position]) {
+		// a sign is only part of the number directly after an exponent
+		r := p.source[p.position]
+		if ('+' == r || '-' == r) && !strings.ContainsRune("eE", p.source[p.position-1]) {
+			break
+		}
+		p.position++
+	}
+	literal := string(p.source[start:p.position])
+	if i, err := strconv.ParseInt(literal, 10, 64); err == nil {
+		return filterLiteral{literal: reflect.ValueOf(i)}, nil
+	}
+	if f, err := strconv.ParseFloat(literal, 64); err == nil {
+		return filterLiteral{literal: reflect.ValueOf(f)}, nil
+	}
+	p.position = start
+	return nil, p.errorf("invalid number %s", literal)
+}
@@ -0,0 +1,74 @@
+package go_path
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+type testPerson struct {
+	Name    string
+	Age     int
+	Score   float64
+	Active  bool
+	Manager *testPerson
+	Tags    map[string]string
+}
+
+func TestFilterExpression(t *testing.T) {
+	person := testPerson{
+		Name:    "ada",
+		Age:     36,
+		Score:   9.5,
+		Active:  true,
+		Manager: &testPerson{Name: "charles"},
+		Tags:    map[string]string{"team": "engines"},
+	}
+	cases := map[string]struct {
+		expression  string
+		expected    bool
+		expectedErr bool
+	}{
+		"greater":              {expression: "@.Age > 21", expected: true},
+		"less or equal":        {expression: "@.Age <= 36", expected: true},
+		"int and float":        {expression: "@.Score >= 9", expected: true},
+		"string equality":      {expression: `@.Name == "ada"`, expected: true},
+		"string ordering":      {expression: `@.Name < "bob"`, expected: true},
+		"boolean":              {expression: "@.Active == true", expected: true},
+		"boolean ordering":     {expression: "@.Active > false"},
+		"mismatched types":     {expression: `@.Age == "36"`},
+		"mismatched not equal": {expression: `@.Age != "36"`, expected: true},
+		"through pointer":      {expression: `@.Manager.Name == "charles"`, expected: true},
+		"map key":              {expression: `@.Tags["team"] == "engines"`, expected: true},
+		"exists":               {expression: "@.Manager", expected: true},
+		"nil does not exist":   {expression: "@.Manager.Manager"},
+		"missing key":          {expression: `@.Tags["role"]`},
+		"null":                 {expression: "@.Manager.Manager == null", expected: true},
+		"missing is not null":  {expression: `@.Tags["role"] == null`},
+		"and":                  {expression: "@.Age > 21 && @.Active", expected: true},
+		"or":                   {expression: "@.Age < 21 || @.Active", expected: true},
+		"not":                  {expression: "!@.Active"},
+		"precedence":           {expression: "@.Age < 21 && @.Active || @.Score > 9", expected: true},
+		"grouping":             {expression: "@.Age < 21 && (@.Active || @.Score > 9)"},
+		"negative number":      {expression: "@.Age > -1e3", expected: true},
+		"element itself":       {expression: "@", expected: true},
+		"unclosed group":       {expression: "(@.Age > 1", expectedErr: true},
+		"lone literal":         {expression: "1", expectedErr: true},
+		"missing operand":      {expression: "@.Age >", expectedErr: true},
+		"trailing operator":    {expression: "@.Age > 1 &&", expectedErr: true},
+		"unknown word":         {expression: "@.Age > yes", expectedErr: true},
+		"pattern path":         {expression: "@.Tags[*]", expectedErr: true},
+		"unterminated string":  {expression: `@.Name == "ada`, expectedErr: true},
+	}
+
+	for caseName, c := range cases {
+		filter, err := NewFilter(c.expression)
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+			continue
+		}
+		require.NoError(t, err, caseName)
+		assert.Equal(t, c.expected, filter.(FilterComponenter).Test(reflect.ValueOf(person)), caseName)
+	}
+}
@@ -0,0 +1,113 @@
+package go_path
+
+import (
+	"reflect"
+)
+
+// Match is a value found by GetAll and the concrete path that locates it
+type Match struct {
+	// Path to the value, containing only components that locate a single value, such as array indexes
+	Path Pather
+	// Value at Path, not dereferenced, as Get would return it
+	Value reflect.Value
+}
+
+// expansion is a single child selected by a component that can select several
+type expansion struct {
+	// path locating value relative to the value that was expanded
+	path []Componenter
+	// value found at path
+	value reflect.Value
+}
+
+// expander is implemented by components that may select more than one child of a value
+type expander interface {
+	Componenter
+	// expand returns each child of v that the component selects, in order. v must already be indirected
+	// The returned error has not been located within the path yet
+	expand(v reflect.Value) ([]expansion, *ResolveError)
+}
+
+// GetAll resolves a path that may contain components selecting several values, such as ranges, wildcards, filters and unions,
+// and returns every value that it matches, in order. Paths without such components match at most one value, like Get.
+// Until the first component that selects several values is reached, failures are reported as a *ResolveError, as Get
+// does. Beneath it, values that the rest of the path does not resolve against are not matched, but are not an error.
+func GetAll(root interface{}, p Pather) ([]Match, error) {
+	m := matcher{
+		parts:   components(p),
+		matches: make([]Match, 0),
+	}
+	if err := m.match(reflect.ValueOf(root), 0, NewRoot(), false); err != nil {
+		return nil, err
+	}
+	return m.matches, nil
+}
+
+// Expand returns the concrete path to every value in root that p matches, in the order GetAll finds them.
+// Each returned path only contains components that locate a single value, so it can be used with Get, Set or Delete
+func Expand(root interface{}, p Pather) ([]Pather, error) {
+	matches, err := GetAll(root, p)
+	if err != nil {
+		return nil, err
+	}
+	expanded := make([]Pather, len(matches))
+	for i, match := range matches {
+		expanded[i] = match.Path
+	}
+	return expanded, nil
+}
+
+// IsConcrete is true if every component of the path locates a single value, so it can be used with Get and Set
+func IsConcrete(p Pather) bool {
+	concrete := true
+	p.Each(func(_ int, componenter Componenter) {
+		if _, ok := componenter.(expander); ok {
+			concrete = false
+		}
+	})
+	return concrete
+}
+
+type matcher struct {
+	parts   []Componenter
+	matches []Match
+}
+
+// match resolves the parts from index onward against v, which was found at resolved
+// expanded is true once a component has selected several values, after which failures are no longer errors
+func (m *matcher) match(v reflect.Value, index int, resolved PathMutator, expanded bool) *ResolveError {
+	if index == len(m.parts) {
+		m.matches = append(m.matches, Match{Path: resolved.Copy(), Value: v})
+		return nil
+	}
+	component := m.parts[index]
+	current, err := indirect(v)
+	if err == nil {
+		if e, ok := component.(expander); ok {
+			var expansions []expansion
+			expansions, err = e.expand(current)
+			if err == nil {
+				for _, child := range expansions {
+					resolved.Append(child.path...)
+					if err = m.match(child.value, index+1, resolved, true); err != nil {
+						return err
+					}
+					resolved.Pop(uint(len(child.path)))
+				}
+				return nil
+			}
+		} else {
+			current, err = resolveComponent(current, component)
+			if err == nil {
+				resolved.Append(component)
+				err = m.match(current, index+1, resolved, expanded)
+				resolved.Pop(1)
+				return err
+			}
+		}
+	}
+	if expanded {
+		return nil
+	}
+	return err.locate(resolved, component)
+}
@@ -0,0 +1,199 @@
+package go_path
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGetAll(t *testing.T) {
+	cases := map[string]struct {
+		path          string
+		expectedPaths []string
+		expected      []interface{}
+		expectedErr   bool
+	}{
+		"concrete": {
+			path:          "Dogs[1].Name",
+			expectedPaths: []string{"Dogs[1].Name"},
+			expected:      []interface{}{"fido"},
+		},
+		"range": {
+			path:          "Dogs[0:2].Name",
+			expectedPaths: []string{"Dogs[0].Name", "Dogs[1].Name"},
+			expected:      []interface{}{"rex", "fido"},
+		},
+		"reversed": {
+			path:          "Counts[::-1]",
+			expectedPaths: []string{"Counts[1]", "Counts[0]"},
+			expected:      []interface{}{7, 3},
+		},
+		"unresolved beneath range are skipped": {
+			path:          "Dogs[:].Owner.Name",
+			expectedPaths: []string{"Dogs[0].Owner.Name"},
+			expected:      []interface{}{"alice"},
+		},
+		"empty range": {
+			path:          "Dogs[5:]",
+			expectedPaths: []string{},
+			expected:      []interface{}{},
+		},
+		"wildcard index": {
+			path:          "Dogs[*].Name",
+			expectedPaths: []string{"Dogs[0].Name", "Dogs[1].Name"},
+			expected:      []interface{}{"rex", "fido"},
+		},
+		"wildcard exported fields": {
+			path:          "Dogs[0].Owner.*",
+			expectedPaths: []string{"Dogs[0].Owner.Name"},
+			expected:      []interface{}{"alice"},
+		},
+		"wildcard map": {
+			path:          "Dogs[0].Attributes.*.Color",
+			expectedPaths: []string{`Dogs[0].Attributes["fur"].Color`},
+			expected:      []interface{}{"brown"},
+		},
+		"nested wildcards": {
+			path:          "Dogs[*].Attributes[*].Tags[*]",
+			expectedPaths: []string{`Dogs[0].Attributes["fur"].Tags[0]`, `Dogs[0].Attributes["fur"].Tags[1]`},
+			expected:      []interface{}{"soft", "short"},
+		},
+		"wildcard of a string": {
+			path:        "Dogs[0].Name[*]",
+			expectedErr: true,
+		},
+		"descendant": {
+			path:          "..Name",
+			expectedPaths: []string{"Dogs[0].Name", "Dogs[0].Owner.Name", "Dogs[1].Name"},
+			expected:      []interface{}{"rex", "alice", "fido"},
+		},
+		"descendant map key": {
+			path:          "Dogs..legs",
+			expectedPaths: []string{`Dogs[0].Extra["legs"]`},
+			expected:      []interface{}{4},
+		},
+		"descendant then field": {
+			path:          "..Attributes.*.Color",
+			expectedPaths: []string{`Dogs[0].Attributes["fur"].Color`},
+			expected:      []interface{}{"brown"},
+		},
+		"filter": {
+			path:          `Dogs[?(@.Owner.Name == "alice")].Name`,
+			expectedPaths: []string{"Dogs[0].Name"},
+			expected:      []interface{}{"rex"},
+		},
+		"filter exists": {
+			path:          "Dogs[?(!@.Owner)].Name",
+			expectedPaths: []string{"Dogs[1].Name"},
+			expected:      []interface{}{"fido"},
+		},
+		"filter map values": {
+			path:          `Dogs[0].Attributes[?(@.Color == "brown")].Tags[0]`,
+			expectedPaths: []string{`Dogs[0].Attributes["fur"].Tags[0]`},
+			expected:      []interface{}{"soft"},
+		},
+		"filter elements themselves": {
+			path:          "Counts[?(@ > 5)]",
+			expectedPaths: []string{"Counts[1]"},
+			expected:      []interface{}{7},
+		},
+		"filter a struct": {
+			path:        "Dogs[0][?(@)]",
+			expectedErr: true,
+		},
+		"field union": {
+			path:          "Dogs[0].{Name,Owner}",
+			expectedPaths: []string{"Dogs[0].Name", "Dogs[0].Owner"},
+			expected:      []interface{}{"rex", &testOwner{Name: "alice"}},
+		},
+		"index union skips missing": {
+			path:          "Dogs[1,5,0].Name",
+			expectedPaths: []string{"Dogs[1].Name", "Dogs[0].Name"},
+			expected:      []interface{}{"fido", "rex"},
+		},
+		"map key union": {
+			path:          `Dogs[0].Attributes["fur","skin"].Color`,
+			expectedPaths: []string{`Dogs[0].Attributes["fur"].Color`},
+			expected:      []interface{}{"brown"},
+		},
+		"union without matches": {
+			path:        "Dogs[5,6]",
+			expectedErr: true,
+		},
+		"unresolved before range": {
+			path:        "Cats[:]",
+			expectedErr: true,
+		},
+		"range of a struct": {
+			path:        "Dogs[0][:]",
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		p, err := Parse(bytes.NewBufferString(c.path))
+		require.NoError(t, err, caseName)
+		matches, err := GetAll(newTestKennel(), p)
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+			continue
+		}
+		require.NoError(t, err, caseName)
+		actualPaths := make([]string, len(matches))
+		actual := make([]interface{}, len(matches))
+		for i, match := range matches {
+			actualPaths[i] = match.Path.String()
+			actual[i] = match.Value.Interface()
+		}
+		assert.Equal(t, c.expectedPaths, actualPaths, caseName)
+		assert.Equal(t, c.expected, actual, caseName)
+	}
+}
+
+func TestGet_Range(t *testing.T) {
+	p, err := Parse(bytes.NewBufferString("Dogs[0:1]"))
+	require.NoError(t, err)
+	assert.False(t, IsConcrete(p))
+	_, err = Get(newTestKennel(), p)
+	assert.Error(t, err)
+}
+
+func TestExpand(t *testing.T) {
+	root := map[int][]string{
+		2: {"b"},
+		1: {"a", "c"},
+	}
+	p, err := Parse(bytes.NewBufferString("[*][*]"))
+	require.NoError(t, err)
+	expanded, err := Expand(root, p)
+	require.NoError(t, err)
+	actual := make([]string, len(expanded))
+	for i, concrete := range expanded {
+		assert.True(t, IsConcrete(concrete))
+		actual[i] = concrete.String()
+	}
+	assert.Equal(t, []string{"[(1)][0]", "[(1)][1]", "[(2)][0]"}, actual)
+}
+
+func TestGetAll_DescendantCycle(t *testing.T) {
+	matches, err := GetAll(newTestList(), New(NewDescendantNamed("Name")))
+	require.NoError(t, err)
+	actual := make([]string, len(matches))
+	for i, match := range matches {
+		actual[i] = match.Path.String()
+	}
+	assert.Equal(t, []string{"Name", "Next.Name"}, actual)
+}
+
+func TestGetAll_DescendantSelfReference(t *testing.T) {
+	s := []interface{}{map[string]interface{}{"name": "a"}, nil}
+	s[1] = s
+	matches, err := GetAll(s, New(NewDescendantNamed("name")))
+	require.NoError(t, err)
+	actual := make([]string, len(matches))
+	for i, match := range matches {
+		actual[i] = match.Path.String()
+	}
+	assert.Equal(t, []string{`[0]["name"]`}, actual)
+}
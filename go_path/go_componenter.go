@@ -6,4 +6,31 @@ import paths "github.com/wojnosystems/go-path"
 type Componenter interface {
 	// Inherit everything about generic Components
 	paths.Componenter
+
+	// Kind of component, which determines the accessor interface it implements
+	Kind() ComponentKind
+
+	// Accept calls the method of the visitor for this kind of component
+	Accept(visitor ComponentVisitor) error
+}
+
+// FieldComponenter is a component that identifies a struct field
+type FieldComponenter interface {
+	Componenter
+	// FieldName is the name of the struct field
+	FieldName() string
+}
+
+// IndexComponenter is a component that identifies a slice or array element
+type IndexComponenter interface {
+	Componenter
+	// Index of the element
+	Index() int
+}
+
+// MapKeyComponenter is a component that identifies a map entry
+type MapKeyComponenter interface {
+	Componenter
+	// Key of the map entry
+	Key() string
 }
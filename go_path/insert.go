@@ -0,0 +1,54 @@
+package go_path
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Insert places value into a slice at the index identified by the last component of the path, shifting the element at
+// that index and all following elements up by one. The index may equal the length of the slice to append. Negative
+// indexes count back from the end, so -1 inserts before the last element.
+// root must be a non-nil pointer and the path must end in an array index.
+func Insert(root interface{}, p Pather, value interface{}) error {
+	rootValue, err := settableRoot(root)
+	if err != nil {
+		return err
+	}
+	parts := components(p)
+	if len(parts) == 0 {
+		return fmt.Errorf("unable to insert at the root")
+	}
+	newValue := reflect.ValueOf(value)
+	return editAt(rootValue, parts, NewRoot(), &setOptions{}, func(container reflect.Value, last Componenter, resolved PathMutator) error {
+		return insertIn(container, last, resolved, newValue)
+	})
+}
+
+func insertIn(container reflect.Value, last Componenter, resolved PathMutator, value reflect.Value) error {
+	c, ok := last.(*pathArrayInstanceVariable)
+	if !ok {
+		return newResolveError(ResolveReasonUnsupportedComponent, container).locate(resolved, last)
+	}
+	if container.Kind() != reflect.Slice {
+		return newResolveError(ResolveReasonKindMismatch, container).locate(resolved, c)
+	}
+	length := container.Len()
+	index := c.index
+	if index < 0 {
+		index += length
+	}
+	if index < 0 || index > length {
+		err := newResolveError(ResolveReasonIndexOutOfRange, container).locate(resolved, c)
+		err.Len = length
+		return err
+	}
+	converted, err := assignableValue(value, container.Type().Elem())
+	if err != nil {
+		return fmt.Errorf("unable to insert at \"%s\": %s", resolved.String(), err.Error())
+	}
+	grown := reflect.Append(container, reflect.Zero(container.Type().Elem()))
+	reflect.Copy(grown.Slice(index+1, length+1), grown.Slice(index, length))
+	grown.Index(index).Set(converted)
+	container.Set(grown)
+	return nil
+}
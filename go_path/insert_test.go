@@ -0,0 +1,107 @@
+package go_path
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestInsert(t *testing.T) {
+	cases := map[string]struct {
+		input       func() *testConfig
+		path        func() Pather
+		value       interface{}
+		expected    func() *testConfig
+		expectedErr bool
+	}{
+		"front": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "b"}, {Host: "c"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(0))
+			},
+			value: testServer{Host: "a"},
+			expected: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {Host: "b"}, {Host: "c"}}}
+			},
+		},
+		"middle": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {Host: "c"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(1))
+			},
+			value: testServer{Host: "b"},
+			expected: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {Host: "b"}, {Host: "c"}}}
+			},
+		},
+		"before last": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {Host: "c"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(-1))
+			},
+			value: testServer{Host: "b"},
+			expected: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {Host: "b"}, {Host: "c"}}}
+			},
+		},
+		"append": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(0))
+			},
+			value: testServer{Host: "a"},
+			expected: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}}}
+			},
+		},
+		"past the end": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(1))
+			},
+			value:       testServer{Host: "a"},
+			expectedErr: true,
+		},
+		"not an index": {
+			input: func() *testConfig {
+				return &testConfig{Labels: map[string]string{}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Labels"), NewMapKey("a"))
+			},
+			value:       "b",
+			expectedErr: true,
+		},
+		"wrong type": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(0))
+			},
+			value:       "a",
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		actual := c.input()
+		err := Insert(actual, c.path(), c.value)
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+		} else {
+			require.NoError(t, err, caseName)
+			assert.Equal(t, c.expected(), actual, caseName)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package go_path
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ApplyJSONMergePatch applies the RFC 7386 JSON Merge Patch document to the value that target points at and returns
+// the paths that were changed.
+// Patch objects are merged member by member into structs (matching fields as JSONPointerToPath does) and maps; null
+// members zero struct fields and remove map keys; any other patch value replaces the value at that location after
+// being decoded into its Go type. Members that do not match a struct field are ignored, as encoding/json does.
+// Paths are only reported when the value there actually changed. Map keys that did not exist before are reported as
+// a single change for the key. Nil pointers that are allocated and values that are replaced by an object are reported
+// where the patch changed them, or at their own path if it changed nothing beneath them. The target is only modified
+// if the whole patch applies.
+func ApplyJSONMergePatch(target interface{}, document []byte) ([]Pather, error) {
+	targetValue, err := settableRoot(target)
+	if err != nil {
+		return nil, err
+	}
+	m := merger{
+		changed: make([]Pather, 0),
+	}
+	working := reflect.New(targetValue.Type()).Elem()
+	working.Set(deepCopy(targetValue))
+	if err = m.merge(working, document, NewRoot()); err != nil {
+		return nil, err
+	}
+	targetValue.Set(working)
+	return m.changed, nil
+}
+
+type merger struct {
+	changed []Pather
+}
+
+// merge applies patch to target, which must be settable
+func (m *merger) merge(target reflect.Value, patch json.RawMessage, p PathMutator) error {
+	if !isJSONObject(patch) {
+		return m.replace(target, patch, p)
+	}
+	switch target.Kind() {
+	case reflect.Ptr:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+			return m.mergeNew(target.Elem(), patch, p)
+		}
+		return m.merge(target.Elem(), patch, p)
+	case reflect.Interface:
+		inner := stripInterfaces(target)
+		if !inner.IsValid() || (inner.Kind() != reflect.Map && inner.Kind() != reflect.Struct && inner.Kind() != reflect.Ptr) {
+			// merging an object into a non-object replaces it with the patch object, without its null members
+			editable := reflect.ValueOf(make(map[string]interface{}))
+			if err := m.mergeNew(editable, patch, p); err != nil {
+				return err
+			}
+			target.Set(editable)
+			return nil
+		}
+		editable := reflect.New(target.Elem().Type()).Elem()
+		editable.Set(target.Elem())
+		if err := m.merge(editable, patch, p); err != nil {
+			return err
+		}
+		target.Set(editable)
+		return nil
+	case reflect.Struct:
+		return m.mergeStruct(target, patch, p)
+	case reflect.Map:
+		return m.mergeMap(target, patch, p)
+	default:
+		return m.replace(target, patch, p)
+	}
+}
+
+func (m *merger) mergeStruct(target reflect.Value, patch json.RawMessage, p PathMutator) error {
+	members, names, err := jsonObjectMembers(patch)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		field, ok := jsonField(target.Type(), name)
+		if !ok {
+			continue
+		}
+		isNull := isJSONNull(members[name])
+		// promoted fields are reached through each of the embedded structs they are promoted from. Nil embedded pointers
+		// are allocated, as encoding/json does, unless the field is being zeroed
+		fieldValue, depth, allocated := target, uint(0), false
+		for _, index := range field.index {
+			if depth != 0 && fieldValue.Kind() == reflect.Ptr {
+				if fieldValue.IsNil() {
+					if isNull {
+						break
+					}
+					if !fieldValue.CanSet() {
+						return fmt.Errorf("unable to merge into \"%s\": cannot allocate an un-exported embedded pointer", p.String())
+					}
+					fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+					allocated = true
+				}
+				fieldValue = fieldValue.Elem()
+			}
+			p.Append(NewInstanceVariableNamed(fieldValue.Type().Field(index).Name))
+			fieldValue = fieldValue.Field(index)
+			depth++
+		}
+		switch {
+		case int(depth) != len(field.index):
+			// the field is promoted through a nil pointer, so it is already zero
+		case isNull:
+			if !fieldValue.IsZero() {
+				fieldValue.Set(reflect.Zero(fieldValue.Type()))
+				m.changed = append(m.changed, p.Copy())
+			}
+		case allocated:
+			if err = m.mergeNew(fieldValue, members[name], p); err != nil {
+				return err
+			}
+		default:
+			if err = m.merge(fieldValue, members[name], p); err != nil {
+				return err
+			}
+		}
+		p.Pop(depth)
+	}
+	return nil
+}
+
+func (m *merger) mergeMap(target reflect.Value, patch json.RawMessage, p PathMutator) error {
+	members, names, err := jsonObjectMembers(patch)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		component, ok := jsonMapKey(target.Type().Key(), name)
+		if !ok {
+			return fmt.Errorf("unable to merge into \"%s\": maps with keys of type %s are not supported", p.String(), target.Type().Key().String())
+		}
+		key, ok := component.keyFor(target.Type().Key())
+		if !ok {
+			return fmt.Errorf("unable to merge into \"%s\": \"%s\" is not a valid key for maps with keys of type %s", p.String(), name, target.Type().Key().String())
+		}
+		p.Append(component)
+		existing := target.MapIndex(key)
+		if isJSONNull(members[name]) {
+			if existing.IsValid() {
+				target.SetMapIndex(key, reflect.Value{})
+				m.changed = append(m.changed, p.Copy())
+			}
+			p.Pop(1)
+			continue
+		}
+		editable := reflect.New(target.Type().Elem()).Elem()
+		if existing.IsValid() {
+			editable.Set(existing)
+			if err = m.merge(editable, members[name], p); err != nil {
+				return err
+			}
+		} else {
+			added := merger{}
+			if err = added.merge(editable, members[name], p); err != nil {
+				return err
+			}
+			m.changed = append(m.changed, p.Copy())
+		}
+		if target.IsNil() {
+			target.Set(reflect.MakeMap(target.Type()))
+		}
+		target.SetMapIndex(key, editable)
+		p.Pop(1)
+	}
+	return nil
+}
+
+// mergeNew applies patch to target, a value that was just allocated or replaced at p. If the patch changes nothing
+// beneath p, the new value itself is reported as the change at p
+func (m *merger) mergeNew(target reflect.Value, patch json.RawMessage, p PathMutator) error {
+	changed := len(m.changed)
+	if err := m.merge(target, patch, p); err != nil {
+		return err
+	}
+	if len(m.changed) == changed {
+		m.changed = append(m.changed, p.Copy())
+	}
+	return nil
+}
+
+// replace decodes patch into a new value of target's type and sets target to it
+func (m *merger) replace(target reflect.Value, patch json.RawMessage, p PathMutator) error {
+	replacement := reflect.New(target.Type())
+	if err := json.Unmarshal(patch, replacement.Interface()); err != nil {
+		return fmt.Errorf("unable to merge into \"%s\": %s", p.String(), err.Error())
+	}
+	if !valuesEqual(target, replacement.Elem()) {
+		target.Set(replacement.Elem())
+		m.changed = append(m.changed, p.Copy())
+	}
+	return nil
+}
+
+// jsonObjectMembers decodes a JSON object, returning its members and their names in sorted order
+func jsonObjectMembers(object json.RawMessage) (members map[string]json.RawMessage, names []string, err error) {
+	if err = json.Unmarshal(object, &members); err != nil {
+		return
+	}
+	names = make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+func isJSONObject(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
@@ -0,0 +1,200 @@
+package go_path
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type testMergeDocument struct {
+	Name   string                 `json:"name"`
+	Server *testPatchServer       `json:"server"`
+	Labels map[string]string      `json:"labels"`
+	Extra  map[string]interface{} `json:"extra"`
+	Tags   []string               `json:"tags"`
+}
+
+func newTestMergeDocument() *testMergeDocument {
+	return &testMergeDocument{
+		Name:   "app",
+		Server: &testPatchServer{Host: "a", Port: 1},
+		Labels: map[string]string{"env": "prod", "team": "core"},
+		Extra:  map[string]interface{}{"nested": map[string]interface{}{"a": 1.0, "b": 2.0}},
+		Tags:   []string{"x", "y"},
+	}
+}
+
+func TestApplyJSONMergePatch(t *testing.T) {
+	cases := map[string]struct {
+		patch           string
+		expected        func() *testMergeDocument
+		expectedChanged []string
+		expectedErr     bool
+	}{
+		"empty": {
+			patch:           `{}`,
+			expected:        newTestMergeDocument,
+			expectedChanged: []string{},
+		},
+		"unchanged value": {
+			patch:           `{"name": "app", "server": {"port": 1}}`,
+			expected:        newTestMergeDocument,
+			expectedChanged: []string{},
+		},
+		"scalar and nested": {
+			patch: `{"name": "web", "server": {"port": 8080}}`,
+			expected: func() *testMergeDocument {
+				d := newTestMergeDocument()
+				d.Name = "web"
+				d.Server.Port = 8080
+				return d
+			},
+			expectedChanged: []string{"Name", "Server.Port"},
+		},
+		"null removes": {
+			patch: `{"labels": {"env": null, "missing": null}, "server": null}`,
+			expected: func() *testMergeDocument {
+				d := newTestMergeDocument()
+				delete(d.Labels, "env")
+				d.Server = nil
+				return d
+			},
+			expectedChanged: []string{"Labels[\"env\"]", "Server"},
+		},
+		"new map key": {
+			patch: `{"labels": {"owner": "ops"}}`,
+			expected: func() *testMergeDocument {
+				d := newTestMergeDocument()
+				d.Labels["owner"] = "ops"
+				return d
+			},
+			expectedChanged: []string{"Labels[\"owner\"]"},
+		},
+		"arrays are replaced": {
+			patch: `{"tags": ["z"]}`,
+			expected: func() *testMergeDocument {
+				d := newTestMergeDocument()
+				d.Tags = []string{"z"}
+				return d
+			},
+			expectedChanged: []string{"Tags"},
+		},
+		"generic maps": {
+			patch: `{"extra": {"nested": {"a": null, "c": 3}}}`,
+			expected: func() *testMergeDocument {
+				d := newTestMergeDocument()
+				d.Extra["nested"] = map[string]interface{}{"b": 2.0, "c": 3.0}
+				return d
+			},
+			expectedChanged: []string{"Extra[\"nested\"][\"a\"]", "Extra[\"nested\"][\"c\"]"},
+		},
+		"allocates pointers": {
+			patch: `{"server": {"host": "b"}}`,
+			expected: func() *testMergeDocument {
+				d := newTestMergeDocument()
+				d.Server.Host = "b"
+				return d
+			},
+			expectedChanged: []string{"Server.Host"},
+		},
+		"type mismatch rolls back": {
+			patch:       `{"name": "web", "server": {"port": "eighty"}}`,
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		actual := newTestMergeDocument()
+		changed, err := ApplyJSONMergePatch(actual, []byte(c.patch))
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+			assert.Equal(t, newTestMergeDocument(), actual, caseName)
+			continue
+		}
+		require.NoError(t, err, caseName)
+		assert.Equal(t, c.expected(), actual, caseName)
+		actualChanged := make([]string, len(changed))
+		for i, p := range changed {
+			actualChanged[i] = p.String()
+		}
+		assert.Equal(t, c.expectedChanged, actualChanged, caseName)
+	}
+}
+
+func TestApplyJSONMergePatch_NilPointer(t *testing.T) {
+	actual := &testMergeDocument{}
+	changed, err := ApplyJSONMergePatch(actual, []byte(`{"server": {"host": "b"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, &testPatchServer{Host: "b"}, actual.Server)
+	require.Len(t, changed, 1)
+	assert.Equal(t, "Server.Host", changed[0].String())
+}
+
+func TestApplyJSONMergePatch_EncodingJSONRules(t *testing.T) {
+	_, err := ApplyJSONMergePatch(&testPatchEmbedding{}, []byte(`{"Extra": true}`))
+	assert.Error(t, err, "encoding/json cannot allocate un-exported embedded pointers either")
+
+	actual := &testPatchEmbedding{testPatchMore: &testPatchMore{}}
+	changed, err := ApplyJSONMergePatch(actual, []byte(`{"id": "x", "Extra": true, "Counts": {"2": "two"}, "Colors": {"blue": 3}}`))
+	require.NoError(t, err)
+	expected := &testPatchEmbedding{
+		testPatchMore: &testPatchMore{Extra: true},
+		Counts:        map[int]string{2: "two"},
+		Colors:        map[testColor]int{testColorBlue: 3},
+	}
+	expected.ID = "x"
+	assert.Equal(t, expected, actual)
+	actualChanged := make([]string, len(changed))
+	for i, p := range changed {
+		actualChanged[i] = p.String()
+	}
+	assert.Equal(t, []string{`Colors[("blue")]`, `Counts[(2)]`, "testPatchMore.Extra", "testPatchBase.ID"}, actualChanged)
+
+	_, err = ApplyJSONMergePatch(actual, []byte(`{"Counts": {"two": "2"}}`))
+	assert.Error(t, err)
+}
+
+func TestApplyJSONMergePatch_EmptyObjects(t *testing.T) {
+	cases := map[string]struct {
+		target          *testMergeDocument
+		patch           string
+		expected        *testMergeDocument
+		expectedChanged []string
+	}{
+		"allocates nil pointer": {
+			target:          &testMergeDocument{},
+			patch:           `{"server": {}}`,
+			expected:        &testMergeDocument{Server: &testPatchServer{}},
+			expectedChanged: []string{"Server"},
+		},
+		"replaces non-object": {
+			target:          &testMergeDocument{Extra: map[string]interface{}{"n": 1.0}},
+			patch:           `{"extra": {"n": {}}}`,
+			expected:        &testMergeDocument{Extra: map[string]interface{}{"n": map[string]interface{}{}}},
+			expectedChanged: []string{`Extra["n"]`},
+		},
+		"replaces non-object without null members": {
+			target:          &testMergeDocument{Extra: map[string]interface{}{"n": 1.0}},
+			patch:           `{"extra": {"n": {"a": null}}}`,
+			expected:        &testMergeDocument{Extra: map[string]interface{}{"n": map[string]interface{}{}}},
+			expectedChanged: []string{`Extra["n"]`},
+		},
+		"existing object": {
+			target:          &testMergeDocument{Server: &testPatchServer{}},
+			patch:           `{"server": {}}`,
+			expected:        &testMergeDocument{Server: &testPatchServer{}},
+			expectedChanged: []string{},
+		},
+	}
+
+	for caseName, c := range cases {
+		changed, err := ApplyJSONMergePatch(c.target, []byte(c.patch))
+		require.NoError(t, err, caseName)
+		assert.Equal(t, c.expected, c.target, caseName)
+		actualChanged := make([]string, len(changed))
+		for i, p := range changed {
+			actualChanged[i] = p.String()
+		}
+		assert.Equal(t, c.expectedChanged, actualChanged, caseName)
+	}
+}
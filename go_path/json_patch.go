@@ -0,0 +1,192 @@
+package go_path
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSONPatchOperation is a single operation of an RFC 6902 JSON Patch document
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatch is an RFC 6902 JSON Patch document
+type JSONPatch []JSONPatchOperation
+
+// JSONPatchError is returned when an operation of a JSONPatch cannot be applied
+type JSONPatchError struct {
+	// Index of the operation in the patch
+	Index int
+	// Operation that failed
+	Operation JSONPatchOperation
+	// Err is why the operation failed
+	Err error
+}
+
+func (e *JSONPatchError) Error() string {
+	return fmt.Sprintf("json patch operation %d (%s \"%s\") failed: %s", e.Index, e.Operation.Op, e.Operation.Path, e.Err.Error())
+}
+
+func (e *JSONPatchError) Unwrap() error {
+	return e.Err
+}
+
+// ParseJSONPatch decodes a JSON Patch document
+func ParseJSONPatch(document []byte) (patch JSONPatch, err error) {
+	err = json.Unmarshal(document, &patch)
+	return
+}
+
+// ApplyJSONPatch decodes the JSON Patch document and applies it to target; see JSONPatch.Apply
+func ApplyJSONPatch(target interface{}, document []byte) error {
+	patch, err := ParseJSONPatch(document)
+	if err != nil {
+		return err
+	}
+	return patch.Apply(target)
+}
+
+// Apply performs each operation of the patch, in order, on the value that target points at.
+// JSON Pointers are translated into Pathers using JSONPointerToPath and values are decoded into the Go type found at
+// the location they are written to. Operations are applied to a copy of the target, which only replaces the target
+// once every operation has succeeded, so a failed patch leaves the target unmodified.
+func (patch JSONPatch) Apply(target interface{}) error {
+	targetValue, err := settableRoot(target)
+	if err != nil {
+		return err
+	}
+	working := reflect.New(targetValue.Type())
+	working.Elem().Set(deepCopy(targetValue))
+	for i, operation := range patch {
+		if err = applyJSONPatchOperation(working, operation); err != nil {
+			return &JSONPatchError{
+				Index:     i,
+				Operation: operation,
+				Err:       err,
+			}
+		}
+	}
+	targetValue.Set(working.Elem())
+	return nil
+}
+
+// applyJSONPatchOperation applies a single operation to the value root points at
+func applyJSONPatchOperation(root reflect.Value, operation JSONPatchOperation) error {
+	switch operation.Op {
+	case "add":
+		value, err := decodeJSONPatchValue(root, operation)
+		if err != nil {
+			return err
+		}
+		return jsonPatchAdd(root, operation.Path, value)
+	case "remove":
+		p, _, err := translateJSONPointer(root, operation.Path)
+		if err != nil {
+			return err
+		}
+		return jsonPatchRemove(root, p)
+	case "replace":
+		value, err := decodeJSONPatchValue(root, operation)
+		if err != nil {
+			return err
+		}
+		p, _, err := translateJSONPointer(root, operation.Path)
+		if err != nil {
+			return err
+		}
+		if _, err = Get(root.Interface(), p); err != nil {
+			return err
+		}
+		return Set(root.Interface(), p, value.Interface())
+	case "move":
+		from, _, err := translateJSONPointer(root, operation.From)
+		if err != nil {
+			return err
+		}
+		value, err := Get(root.Interface(), from)
+		if err != nil {
+			return err
+		}
+		value = deepCopy(value)
+		if err = jsonPatchRemove(root, from); err != nil {
+			return err
+		}
+		return jsonPatchAdd(root, operation.Path, value)
+	case "copy":
+		from, _, err := translateJSONPointer(root, operation.From)
+		if err != nil {
+			return err
+		}
+		value, err := Get(root.Interface(), from)
+		if err != nil {
+			return err
+		}
+		return jsonPatchAdd(root, operation.Path, deepCopy(value))
+	case "test":
+		value, err := decodeJSONPatchValue(root, operation)
+		if err != nil {
+			return err
+		}
+		p, _, err := translateJSONPointer(root, operation.Path)
+		if err != nil {
+			return err
+		}
+		actual := root.Elem()
+		if len(components(p)) != 0 {
+			if actual, err = Get(root.Interface(), p); err != nil {
+				return err
+			}
+		}
+		if !valuesEqual(actual, value) {
+			return fmt.Errorf("test failed: value at \"%s\" is not equal", p.String())
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown operation \"%s\"", operation.Op)
+	}
+}
+
+// decodeJSONPatchValue decodes the operation's value into the Go type at the operation's path. root points at the
+// document, so the empty path, which replaces the whole document, has the type root points at
+func decodeJSONPatchValue(root reflect.Value, operation JSONPatchOperation) (reflect.Value, error) {
+	if operation.Value == nil {
+		return reflect.Value{}, fmt.Errorf("operation \"%s\" requires a value", operation.Op)
+	}
+	_, valueType, err := translateJSONPointer(root.Elem(), operation.Path)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	value := reflect.New(valueType)
+	if err = json.Unmarshal(operation.Value, value.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return value.Elem(), nil
+}
+
+// jsonPatchAdd inserts value into a slice, or sets the map key or struct field, at pointer
+func jsonPatchAdd(root reflect.Value, pointer string, value reflect.Value) error {
+	p, _, err := translateJSONPointer(root, pointer)
+	if err != nil {
+		return err
+	}
+	parts := components(p)
+	if len(parts) == 0 {
+		return Set(root.Interface(), p, interfaceOrNil(value))
+	}
+	if _, isIndex := parts[len(parts)-1].(*pathArrayInstanceVariable); isIndex {
+		return Insert(root.Interface(), p, interfaceOrNil(value))
+	}
+	return Set(root.Interface(), p, interfaceOrNil(value), SetCreateMaps())
+}
+
+// jsonPatchRemove removes the value at p, which must exist
+func jsonPatchRemove(root reflect.Value, p Pather) error {
+	if _, err := Get(root.Interface(), p); err != nil {
+		return err
+	}
+	return Delete(root.Interface(), p)
+}
@@ -0,0 +1,354 @@
+package go_path
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type testPatchServer struct {
+	Host string `json:"host"`
+	Port int    `json:"port,omitempty"`
+}
+
+type testPatchDocument struct {
+	Name    string                 `json:"name"`
+	Servers []testPatchServer      `json:"servers"`
+	Tags    []string               `json:"tags"`
+	Labels  map[string]string      `json:"labels"`
+	Extra   map[string]interface{} `json:"extra"`
+	Secret  string                 `json:"-"`
+	Count   int
+}
+
+func newTestPatchDocument() *testPatchDocument {
+	return &testPatchDocument{
+		Name:    "app",
+		Servers: []testPatchServer{{Host: "a", Port: 1}, {Host: "b", Port: 2}},
+		Tags:    []string{"a", "b", "c"},
+		Labels:  map[string]string{"env": "prod"},
+		Extra:   map[string]interface{}{"list": []interface{}{1.0, "x"}},
+		Count:   3,
+	}
+}
+
+func TestJSONPointerToPath(t *testing.T) {
+	cases := map[string]struct {
+		pointer     string
+		expected    string
+		expectedErr bool
+	}{
+		"root": {
+			pointer:  "",
+			expected: "",
+		},
+		"tag name": {
+			pointer:  "/servers/1/host",
+			expected: "Servers[1].Host",
+		},
+		"go name": {
+			pointer:  "/count",
+			expected: "Count",
+		},
+		"map key": {
+			pointer:  "/labels/env",
+			expected: "Labels[\"env\"]",
+		},
+		"escaped map key": {
+			pointer:  "/labels/a~1b~0c",
+			expected: "Labels[\"a/b~c\"]",
+		},
+		"end of slice": {
+			pointer:  "/servers/-",
+			expected: "Servers[2]",
+		},
+		"through interface": {
+			pointer:  "/extra/list/1",
+			expected: "Extra[\"list\"][1]",
+		},
+		"ignored field": {
+			pointer:     "/Secret",
+			expectedErr: true,
+		},
+		"leading zero": {
+			pointer:     "/servers/01",
+			expectedErr: true,
+		},
+		"missing parent": {
+			pointer:     "/labels/env/x",
+			expectedErr: true,
+		},
+		"no slash": {
+			pointer:     "name",
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		actual, err := JSONPointerToPath(newTestPatchDocument(), c.pointer)
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+		} else {
+			require.NoError(t, err, caseName)
+			assert.Equal(t, c.expected, actual.String(), caseName)
+		}
+	}
+}
+
+type testPatchBase struct {
+	ID       string `json:"id"`
+	Promoted int
+	Shadowed string
+	Tagged   string
+}
+
+type testPatchMore struct {
+	Extra    bool
+	Shadowed string
+	Tagged   string `json:"tagged"`
+	Twice    int
+}
+
+type testPatchTwice struct {
+	Twice int
+}
+
+type testPatchEmbedding struct {
+	testPatchBase
+	*testPatchMore
+	testPatchTwice
+	Shadowed string
+	Counts   map[int]string
+	Colors   map[testColor]int
+}
+
+func TestJSONPointerToPath_EncodingJSONRules(t *testing.T) {
+	cases := map[string]struct {
+		root        interface{}
+		pointer     string
+		expected    string
+		expectedErr bool
+	}{
+		"promoted": {
+			pointer:  "/Promoted",
+			expected: "testPatchBase.Promoted",
+		},
+		"promoted tag": {
+			pointer:  "/id",
+			expected: "testPatchBase.ID",
+		},
+		"promoted through nil pointer": {
+			pointer:  "/Extra",
+			expected: "testPatchMore.Extra",
+		},
+		"promoted through pointer": {
+			root:     &testPatchEmbedding{testPatchMore: &testPatchMore{}},
+			pointer:  "/Extra",
+			expected: "testPatchMore.Extra",
+		},
+		"shallower field wins": {
+			pointer:  "/Shadowed",
+			expected: "Shadowed",
+		},
+		"tagged field wins": {
+			pointer:  "/tagged",
+			expected: "testPatchMore.Tagged",
+		},
+		"conflicting fields are ignored": {
+			pointer:     "/Twice",
+			expectedErr: true,
+		},
+		"integer key": {
+			pointer:  "/Counts/-3",
+			expected: "Counts[(-3)]",
+		},
+		"invalid integer key": {
+			pointer:     "/Counts/x",
+			expectedErr: true,
+		},
+		"text key": {
+			pointer:  "/Colors/blue",
+			expected: `Colors[("blue")]`,
+		},
+		"invalid text key": {
+			pointer:     "/Colors/1",
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		if c.root == nil {
+			c.root = &testPatchEmbedding{}
+		}
+		actual, err := JSONPointerToPath(c.root, c.pointer)
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+		} else {
+			require.NoError(t, err, caseName)
+			assert.Equal(t, c.expected, actual.String(), caseName)
+		}
+	}
+}
+
+func TestJSONPatch_ApplyEncodingJSONRules(t *testing.T) {
+	actual := &testPatchEmbedding{Counts: map[int]string{}, Colors: map[testColor]int{}}
+	err := ApplyJSONPatch(actual, []byte(`[
+		{"op": "add", "path": "/Promoted", "value": 1},
+		{"op": "add", "path": "/Counts/2", "value": "two"},
+		{"op": "add", "path": "/Colors/blue", "value": 3}
+	]`))
+	require.NoError(t, err)
+	expected := &testPatchEmbedding{
+		Counts: map[int]string{2: "two"},
+		Colors: map[testColor]int{testColorBlue: 3},
+	}
+	expected.Promoted = 1
+	assert.Equal(t, expected, actual)
+}
+
+func TestJSONPatch_Apply(t *testing.T) {
+	cases := map[string]struct {
+		patch       string
+		expected    func() *testPatchDocument
+		expectedErr bool
+	}{
+		"add to slice": {
+			patch: `[{"op": "add", "path": "/servers/1", "value": {"host": "c"}}]`,
+			expected: func() *testPatchDocument {
+				d := newTestPatchDocument()
+				d.Servers = []testPatchServer{{Host: "a", Port: 1}, {Host: "c"}, {Host: "b", Port: 2}}
+				return d
+			},
+		},
+		"append to slice": {
+			patch: `[{"op": "add", "path": "/servers/-", "value": {"host": "c"}}]`,
+			expected: func() *testPatchDocument {
+				d := newTestPatchDocument()
+				d.Servers = append(d.Servers, testPatchServer{Host: "c"})
+				return d
+			},
+		},
+		"add map key": {
+			patch: `[{"op": "add", "path": "/labels/team", "value": "core"}]`,
+			expected: func() *testPatchDocument {
+				d := newTestPatchDocument()
+				d.Labels["team"] = "core"
+				return d
+			},
+		},
+		"remove": {
+			patch: `[{"op": "remove", "path": "/servers/0"}, {"op": "remove", "path": "/labels/env"}]`,
+			expected: func() *testPatchDocument {
+				d := newTestPatchDocument()
+				d.Servers = []testPatchServer{{Host: "b", Port: 2}}
+				d.Labels = map[string]string{}
+				return d
+			},
+		},
+		"replace": {
+			patch: `[{"op": "replace", "path": "/servers/0/port", "value": 8080}]`,
+			expected: func() *testPatchDocument {
+				d := newTestPatchDocument()
+				d.Servers[0].Port = 8080
+				return d
+			},
+		},
+		"move": {
+			patch: `[{"op": "move", "from": "/servers/0", "path": "/servers/-"}]`,
+			expected: func() *testPatchDocument {
+				d := newTestPatchDocument()
+				d.Servers = []testPatchServer{{Host: "b", Port: 2}, {Host: "a", Port: 1}}
+				return d
+			},
+		},
+		"move scalar forward": {
+			patch: `[{"op": "move", "from": "/tags/0", "path": "/tags/1"}]`,
+			expected: func() *testPatchDocument {
+				d := newTestPatchDocument()
+				d.Tags = []string{"b", "a", "c"}
+				return d
+			},
+		},
+		"move scalar to end": {
+			patch: `[{"op": "move", "from": "/tags/0", "path": "/tags/2"}]`,
+			expected: func() *testPatchDocument {
+				d := newTestPatchDocument()
+				d.Tags = []string{"b", "c", "a"}
+				return d
+			},
+		},
+		"copy": {
+			patch: `[{"op": "copy", "from": "/name", "path": "/labels/name"}]`,
+			expected: func() *testPatchDocument {
+				d := newTestPatchDocument()
+				d.Labels["name"] = "app"
+				return d
+			},
+		},
+		"test passes": {
+			patch: `[{"op": "test", "path": "/servers/1", "value": {"host": "b", "port": 2}}, {"op": "replace", "path": "/name", "value": "x"}]`,
+			expected: func() *testPatchDocument {
+				d := newTestPatchDocument()
+				d.Name = "x"
+				return d
+			},
+		},
+		"add root": {
+			patch: `[{"op": "add", "path": "", "value": {"name": "new", "tags": ["x"]}}]`,
+			expected: func() *testPatchDocument {
+				return &testPatchDocument{Name: "new", Tags: []string{"x"}}
+			},
+		},
+		"replace root": {
+			patch: `[{"op": "replace", "path": "", "value": {"name": "new"}}, {"op": "add", "path": "/count", "value": 1}]`,
+			expected: func() *testPatchDocument {
+				return &testPatchDocument{Name: "new", Count: 1}
+			},
+		},
+		"test root": {
+			patch: `[{"op": "test", "path": "", "value": {"name": "app", "servers": [{"host": "a", "port": 1}, {"host": "b", "port": 2}], "tags": ["a", "b", "c"], "labels": {"env": "prod"}, "extra": {"list": [1, "x"]}, "Count": 3}}]`,
+			expected: func() *testPatchDocument {
+				return newTestPatchDocument()
+			},
+		},
+		"test fails and rolls back": {
+			patch:       `[{"op": "replace", "path": "/name", "value": "x"}, {"op": "test", "path": "/count", "value": 4}]`,
+			expectedErr: true,
+		},
+		"replace missing": {
+			patch:       `[{"op": "replace", "path": "/labels/missing", "value": "x"}]`,
+			expectedErr: true,
+		},
+		"wrong type": {
+			patch:       `[{"op": "replace", "path": "/count", "value": "x"}]`,
+			expectedErr: true,
+		},
+		"unknown op": {
+			patch:       `[{"op": "frobnicate", "path": "/count"}]`,
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		actual := newTestPatchDocument()
+		err := ApplyJSONPatch(actual, []byte(c.patch))
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+			assert.Equal(t, newTestPatchDocument(), actual, caseName)
+		} else {
+			require.NoError(t, err, caseName)
+			assert.Equal(t, c.expected(), actual, caseName)
+		}
+	}
+}
+
+func TestJSONPatch_ErrorIdentifiesOperation(t *testing.T) {
+	err := ApplyJSONPatch(newTestPatchDocument(), []byte(`[{"op": "remove", "path": "/name"}, {"op": "remove", "path": "/servers/5"}]`))
+	var patchErr *JSONPatchError
+	require.True(t, errors.As(err, &patchErr))
+	assert.Equal(t, 1, patchErr.Index)
+	var resolveErr *ResolveError
+	require.True(t, errors.As(err, &resolveErr))
+	assert.Equal(t, ResolveReasonIndexOutOfRange, resolveErr.Reason)
+}
@@ -0,0 +1,299 @@
+package go_path
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPointerToPath translates an RFC 6901 JSON Pointer into the Pather that identifies the same location in root.
+// JSON object members are matched to struct fields the way encoding/json does: by their json tag name, or by their
+// Go field name, case insensitively, including the fields promoted from embedded structs. They are matched to map keys
+// through encoding.TextUnmarshaler if the key type implements it, and otherwise as strings or integers. The final token may refer to a map key or slice index that does not exist yet,
+// and may be "-" to refer to the index just past the end of a slice.
+func JSONPointerToPath(root interface{}, pointer string) (PathMutator, error) {
+	p, _, err := translateJSONPointer(reflect.ValueOf(root), pointer)
+	return p, err
+}
+
+// translateJSONPointer translates pointer into a path within root, also returning the type of the value at the path
+func translateJSONPointer(root reflect.Value, pointer string) (PathMutator, reflect.Type, error) {
+	tokens, err := jsonPointerTokens(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := NewRoot()
+	current := root
+	var currentType reflect.Type
+	if root.IsValid() {
+		currentType = root.Type()
+	}
+	for i, token := range tokens {
+		if !current.IsValid() {
+			return nil, nil, fmt.Errorf("json pointer \"%s\": \"%s\" does not exist", pointer, p.String())
+		}
+		var resolveErr *ResolveError
+		current, resolveErr = indirect(current)
+		if resolveErr != nil {
+			return nil, nil, fmt.Errorf("json pointer \"%s\": %s", pointer, resolveErr.locate(p, NewInstanceVariableNamed(token)).Error())
+		}
+		isLast := i == len(tokens)-1
+		switch current.Kind() {
+		case reflect.Struct:
+			field, ok := jsonField(current.Type(), token)
+			if !ok {
+				return nil, nil, fmt.Errorf("json pointer \"%s\": %s has no field for \"%s\"", pointer, current.Type().String(), token)
+			}
+			// promoted fields are reached through each of the embedded structs they are promoted from
+			structType := current.Type()
+			for depth, index := range field.index {
+				if depth != 0 {
+					if structType.Kind() == reflect.Ptr {
+						structType = structType.Elem()
+					}
+					if current, resolveErr = indirect(current); resolveErr != nil {
+						// a nil embedded pointer, the promoted fields do not exist yet
+						current = reflect.Value{}
+					}
+				}
+				structField := structType.Field(index)
+				p.Append(NewInstanceVariableNamed(structField.Name))
+				structType = structField.Type
+				currentType = structField.Type
+				if current.IsValid() {
+					current = current.Field(index)
+				}
+			}
+		case reflect.Slice, reflect.Array:
+			index := current.Len()
+			if token != "-" {
+				index, err = jsonPointerIndex(token)
+				if err != nil {
+					return nil, nil, fmt.Errorf("json pointer \"%s\": %s", pointer, err.Error())
+				}
+			} else if !isLast {
+				return nil, nil, fmt.Errorf("json pointer \"%s\": \"-\" may only be the last token", pointer)
+			}
+			p.Append(NewArrayIndex(index))
+			currentType = current.Type().Elem()
+			if index < current.Len() {
+				current = current.Index(index)
+			} else {
+				current = reflect.Value{}
+			}
+		case reflect.Map:
+			component, ok := jsonMapKey(current.Type().Key(), token)
+			if !ok {
+				return nil, nil, fmt.Errorf("json pointer \"%s\": maps with keys of type %s are not supported", pointer, current.Type().Key().String())
+			}
+			key, ok := component.keyFor(current.Type().Key())
+			if !ok {
+				return nil, nil, fmt.Errorf("json pointer \"%s\": \"%s\" is not a valid key for maps with keys of type %s", pointer, token, current.Type().Key().String())
+			}
+			p.Append(component)
+			currentType = current.Type().Elem()
+			current = current.MapIndex(key)
+		default:
+			return nil, nil, fmt.Errorf("json pointer \"%s\": cannot descend into %s at \"%s\"", pointer, current.Type().String(), p.String())
+		}
+	}
+	return p, currentType, nil
+}
+
+// jsonPointerTokens splits a JSON pointer into its unescaped reference tokens
+func jsonPointerTokens(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("json pointer \"%s\" must be empty or start with \"/\"", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// jsonPointerIndex parses an array index token, which may not have leading zeros
+func jsonPointerIndex(token string) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("\"%s\" is not a valid array index", token)
+	}
+	index, err := strconv.ParseUint(token, 10, 31)
+	if err != nil {
+		return 0, fmt.Errorf("\"%s\" is not a valid array index", token)
+	}
+	return int(index), nil
+}
+
+// jsonMapKey creates the component for a map key the way encoding/json decodes object member names into keys of
+// keyType: through encoding.TextUnmarshaler if the key type implements it, otherwise as a string or an integer.
+// ok is false if encoding/json does not support the key type
+func jsonMapKey(keyType reflect.Type, token string) (component mapKeyer, ok bool) {
+	if reflect.PtrTo(keyType).Implements(textUnmarshalerType) {
+		return &pathTypedMapKey{literal: strconv.Quote(token)}, true
+	}
+	switch keyType.Kind() {
+	case reflect.String, reflect.Interface:
+		return NewMapKey(token).(mapKeyer), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &pathTypedMapKey{literal: token}, true
+	}
+	return nil, false
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// jsonStructField is a field that encoding/json encodes and decodes as an object member
+type jsonStructField struct {
+	name string
+	// index is the sequence of field indexes leading to the field, through the embedded structs it was promoted from
+	index  []int
+	tagged bool
+}
+
+// valueIn returns the field of v, a struct, ok is false if the field is promoted through a nil pointer
+func (f jsonStructField) valueIn(v reflect.Value) (field reflect.Value, ok bool) {
+	for depth, index := range f.index {
+		if depth != 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(index)
+	}
+	return v, true
+}
+
+// jsonField finds the struct field that encoding/json would decode the object member name into: a field with exactly
+// that name, or else the first with that name ignoring case
+func jsonField(t reflect.Type, name string) (jsonStructField, bool) {
+	fields := jsonFields(t)
+	for _, field := range fields {
+		if field.name == name {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field.name, name) {
+			return field, true
+		}
+	}
+	return jsonStructField{}, false
+}
+
+// jsonFields lists the fields of t that encoding/json encodes, in field order, including the fields promoted from
+// embedded structs without a json name. As with encoding/json, of the fields sharing a name, the least deeply embedded
+// one is used, preferring a field with a json tag. If that still leaves more than one, none of them are used
+func jsonFields(t reflect.Type) []jsonStructField {
+	candidates := make([]jsonStructField, 0)
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	next := []embedded{{typ: t}}
+	visited := make(map[reflect.Type]bool)
+	for len(next) != 0 {
+		current := next
+		next = nil
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			for i := 0; i < e.typ.NumField(); i++ {
+				field := e.typ.Field(i)
+				fieldType := field.Type
+				if fieldType.Kind() == reflect.Ptr {
+					fieldType = fieldType.Elem()
+				}
+				if field.PkgPath != "" && !(field.Anonymous && fieldType.Kind() == reflect.Struct) {
+					continue
+				}
+				tag := field.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				index := make([]int, len(e.index)+1)
+				copy(index, e.index)
+				index[len(e.index)] = i
+				tagName := strings.Split(tag, ",")[0]
+				if tagName == "" && field.Anonymous && fieldType.Kind() == reflect.Struct {
+					// a struct embedded twice at the same depth is searched twice, so that its fields conflict
+					next = append(next, embedded{typ: fieldType, index: index})
+					continue
+				}
+				if field.PkgPath != "" {
+					// an un-exported embedded struct is only searched for promoted fields
+					continue
+				}
+				candidate := jsonStructField{name: field.Name, index: index, tagged: tagName != ""}
+				if candidate.tagged {
+					candidate.name = tagName
+				}
+				candidates = append(candidates, candidate)
+			}
+		}
+		for _, e := range current {
+			visited[e.typ] = true
+		}
+	}
+
+	fields := make([]jsonStructField, 0, len(candidates))
+	for _, candidate := range candidates {
+		if dominant, ok := dominantJSONField(candidates, candidate.name); ok && sameIndex(dominant.index, candidate.index) {
+			fields = append(fields, candidate)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return indexLess(fields[i].index, fields[j].index)
+	})
+	return fields
+}
+
+// dominantJSONField finds the field called name that encoding/json uses, ok is false if there is no single such field
+func dominantJSONField(candidates []jsonStructField, name string) (dominant jsonStructField, ok bool) {
+	count := 0
+	for _, candidate := range candidates {
+		if candidate.name != name {
+			continue
+		}
+		switch {
+		case count == 0,
+			len(candidate.index) < len(dominant.index),
+			len(candidate.index) == len(dominant.index) && candidate.tagged && !dominant.tagged:
+			dominant = candidate
+			count = 1
+		case len(candidate.index) == len(dominant.index) && candidate.tagged == dominant.tagged:
+			count++
+		}
+	}
+	return dominant, count == 1
+}
+
+func sameIndex(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// indexLess orders field index sequences the way the fields are declared
+func indexLess(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"github.com/wojnosystems/go-optional"
 	"io"
+	"sync"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Parses go-path strings into the Pather object that it represents
@@ -14,8 +16,16 @@ import (
 // There are 3 object types (so far): Structs, Arrays (slices), and Maps.
 // Roots. Each of the above type may be a root, in which case, the string looks like:
 // * nameOfVarInStruct
-// * [indexOfArray]
+// * [indexOfArray], which may be negative to count back from the end: [-1] is the last element
 // * ["keyOfMap"]
+// * [start:end:step] for a range of array indexes, where each part is optional: [1:3], [::2], [-2:]
+// * 'my-field' for a field whose name is not only letters, digits and underscores, with \' and \\ as escapes
+// * ..name for every field or map key called name at any depth
+// * * or [*] for every field, element or map entry: users[*].email, settings.*
+// * [?(@.age > 21)] for the elements of a slice, array or map that satisfy an expression, see NewFilter
+// * [field="value"] for the element of a slice whose field has the value, see NewKeyedElement
+// * {name,email} for several fields, and [0,3,7] or ["a","b"] for several indexes, map keys or keyed elements
+// * [(42)] for maps with keys that are not strings, see NewMapKeyOf
 // Struct roots do not have the leading dot, but the dot separates structs from each other:
 // * nameOfVar.anotherVar.yetAnotherVar to indicate a nested struct like:
 // type Third struct {
@@ -27,9 +37,11 @@ import (
 // type Root struct {
 //   nameOfVar Second
 // }
-// This is a very simple lexxer as the grammar does not support nested square brackets or anything else that's nested within itself, so keeping a paren level is not necessary
+// This is a very simple lexxer as the path grammar does not support nested square brackets. The only nesting is within
+// filter expressions, [?(...)], whose parentheses may group sub-expressions. The filter lexer keeps a paren level,
+// ignoring parentheses within strings, to find the parenthesis that closes the filter
 //
-// newlines are ignored at this time
+// Whitespace is an error, unless ParseOptions.AllowWhitespace is set, in which case it may surround any token
 
 type token int
 
@@ -38,25 +50,43 @@ const (
 	itemEOF
 
 	literalBegin
-	itemArrayIndex // 12345
-	itemMapKey     // key
+	itemArrayIndex  // 12345
+	itemArrayRange  // 1:3:2
+	itemMapKey      // key
+	itemTypedMapKey // 42, true or "text"
+	itemFilter      // @.age > 21
+	itemKeyed       // name="web"
+	itemFieldUnion  // name,email
 	literalEnd
 
-	itemVariableName // variableName
+	itemVariableName  // variableName
+	itemWildcard      // *
+	itemDescendant    // ..name
+	itemIndexWildcard // [*]
 
 	symbolBegin
 	itemDot                // .
 	itemQuoteDouble        // "
 	itemSquareBracketOpen  // [
 	itemSquareBracketClose // ]
+	itemColon              // :
+	itemAsterisk           // *
+	itemQuestion           // ?
+	itemEquals             // =
+	itemComma              // ,
+	itemBraceOpen          // {
+	itemBraceClose         // }
+	itemParenOpen          // (
+	itemParenClose         // )
 	symbolEnd
 )
 
 type item struct {
-	typ  token
-	val  string
-	line uint
-	col  uint
+	typ    token
+	val    string
+	line   uint
+	col    uint
+	offset int // in bytes
 }
 
 func (i item) String() string {
@@ -81,10 +111,21 @@ var key = map[string]token{
 	"]":  itemSquareBracketClose,
 	".":  itemDot,
 	"\"": itemQuoteDouble,
+	":":  itemColon,
+	"*":  itemAsterisk,
+	"?":  itemQuestion,
+	"=":  itemEquals,
+	",":  itemComma,
+	"{":  itemBraceOpen,
+	"}":  itemBraceClose,
+	"(":  itemParenOpen,
+	")":  itemParenClose,
 }
 
 type lexerState struct {
 	parse func(l *lexer) *lexerState
+	// expected describes what the state accepts, for errors
+	expected []string
 }
 
 var (
@@ -95,69 +136,164 @@ var (
 	stateItemSquareBracketClose *lexerState
 	stateItemMapKey             *lexerState
 	stateItemMapKeyEnd          *lexerState
+	stateItemTypedMapKey        *lexerState
+	stateItemFilterStart        *lexerState
+	stateItemKeyedField         *lexerState
+	stateItemFieldUnion         *lexerState
+	stateItemKeyedValue         *lexerState
+	stateItemFilter             *lexerState
 	stateItemArrayIndex         *lexerState
 	stateItemDot                *lexerState
+	stateItemWildcard           *lexerState
+	stateItemDescendantDot      *lexerState
+	stateItemDescendant         *lexerState
+	stateItemDescendantStart    *lexerState
+	stateItemIndexWildcard      *lexerState
+	stateItemQuotedName         *lexerState
+	stateItemQuotedDescendant   *lexerState
 	stateStart                  *lexerState
 )
 
+// lexer is pulled by the parser: each call to getNextItem runs the state machine until it has emitted an item.
+// Lexers are pooled, see newLexer and release
 type lexer struct {
-	source       *bufio.Reader
+	source io.RuneReader
+	// buffered wraps sources that are not io.RuneReaders, and is kept with the lexer when it is pooled
+	buffered *bufio.Reader
+	// input is read instead of source when isString is set, and values are sliced from it instead of copied
+	input        string
+	isString     bool
+	options      ParseOptions
 	peeked       optional.Rune
 	currentState *lexerState
 	line         uint // 1 + number of newlines seen
 	col          uint
 	startCol     uint
-	itemEmitter  chan item
 	currentValue []rune
 	startLine    uint
+	offset       int // bytes consumed
+	startOffset  int
+	peekedSize   int
+	// valueStart and valueEnd are the offsets of the bytes of currentValue in input, unless isGapped is set because
+	// runes were ignored within the value or the value was changed
+	valueStart int
+	valueEnd   int
+	isGapped   bool
+	// err describes the error once itemError has been emitted
+	err *ParseError
+	// items emitted by the current state that have not been returned by getNextItem yet, from next onward
+	items []item
+	next  int
+	// backing arrays, so that typical paths are lexed without allocating
+	valueBuffer [64]rune
+	itemBuffer  [2]item
 }
 
-func newLexer(source io.Reader) lexer {
-	return lexer{
-		source:       bufio.NewReader(source),
+var lexerPool = sync.Pool{
+	New: func() interface{} {
+		return &lexer{}
+	},
+}
+
+// newLexer returns a lexer reading source, which should be released once it is no longer used
+func newLexer(source io.Reader, options ParseOptions) *lexer {
+	l := acquireLexer(options)
+	runeSource, ok := source.(io.RuneReader)
+	if !ok {
+		if l.buffered == nil {
+			l.buffered = bufio.NewReader(source)
+		} else {
+			l.buffered.Reset(source)
+		}
+		runeSource = l.buffered
+	}
+	l.source = runeSource
+	return l
+}
+
+// newStringLexer returns a lexer reading input, which should be released once it is no longer used
+func newStringLexer(input string, options ParseOptions) *lexer {
+	l := acquireLexer(options)
+	l.input = input
+	l.isString = true
+	return l
+}
+
+func acquireLexer(options ParseOptions) *lexer {
+	l := lexerPool.Get().(*lexer)
+	*l = lexer{
+		buffered:     l.buffered,
+		options:      options,
 		currentState: stateStart,
-		itemEmitter:  make(chan item),
 		line:         1,
 		col:          1,
 		startLine:    1,
 		startCol:     1,
-		currentValue: make([]rune, 0, IdentMax),
 	}
+	l.currentValue = l.valueBuffer[:0]
+	l.items = l.itemBuffer[:0]
+	return l
+}
+
+// release returns the lexer to the pool. Neither the lexer nor its err may be used afterwards
+func (l *lexer) release() {
+	l.source = nil
+	l.input = ""
+	l.err = nil
+	if l.buffered != nil {
+		l.buffered.Reset(nil)
+	}
+	lexerPool.Put(l)
 }
 
 func (l *lexer) appendCurrent(r rune) error {
-	if len(l.currentValue) == cap(l.currentValue) {
+	if len(l.currentValue) >= IdentMax {
 		return errors.New("identifier longer than our maximum buffer size")
 	}
 	l.currentValue = append(l.currentValue, r)
 	return nil
 }
 
-func (l *lexer) lex() {
-	for state := stateStart; state != nil && state != stateError; {
-		state = state.parse(l)
-	}
-	close(l.itemEmitter)
-}
-
 func (l *lexer) peek() (r rune, err error) {
 	if l.peeked.IsSet() {
 		r = l.peeked.Value()
 		return
 	}
-	r, _, err = l.source.ReadRune()
-	if err != nil {
+	if l.isString {
+		if l.offset == len(l.input) {
+			err = io.EOF
+			return
+		}
+		r, l.peekedSize = utf8.DecodeRuneInString(l.input[l.offset:])
+	} else {
+		r, l.peekedSize, err = l.source.ReadRune()
+		if err != nil {
+			return
+		}
+	}
+	if l.options.MaxLength > 0 && l.offset+l.peekedSize > l.options.MaxLength {
+		err = &LengthLimitError{Limit: l.options.MaxLength}
 		return
 	}
 	l.peeked.Set(r)
 	return
 }
 
-// ignore the last rune appended
+// ignore the last rune peeked. Runes ignored before a value is accepted are not part of the next item's position
 func (l *lexer) ignore() {
 	if l.peeked.IsSet() {
-		l.peeked.Unset()
 		l.col++
+		if l.peeked.Value() == '\n' {
+			l.line++
+			l.col = 1
+		}
+		l.peeked.Unset()
+		l.offset += l.peekedSize
+	}
+	if len(l.currentValue) == 0 {
+		l.startLine = l.line
+		l.startCol = l.col
+		l.startOffset = l.offset
 	}
 }
 
@@ -165,11 +301,18 @@ func (l *lexer) accept() (err error) {
 	var r rune
 	if l.peeked.IsSet() {
 		r = l.peeked.Value()
-		l.peeked.Unset()
+		if len(l.currentValue) == 0 {
+			l.valueStart = l.offset
+		} else if l.valueEnd != l.offset {
+			l.isGapped = true
+		}
 		err = l.appendCurrent(r)
 		if err != nil {
 			return
 		}
+		l.peeked.Unset()
+		l.offset += l.peekedSize
+		l.valueEnd = l.offset
 	}
 	l.col++
 	if r == '\n' {
@@ -179,49 +322,108 @@ func (l *lexer) accept() (err error) {
 	return
 }
 
+// skipWhitespace ignores the whitespace before the next token, if the options allow it, and returns true if there was any
+func (l *lexer) skipWhitespace() (skipped bool) {
+	for {
+		r, err := l.peek()
+		if err != nil || !l.options.isWhitespace(r) {
+			return
+		}
+		l.ignore()
+		skipped = true
+	}
+}
+
 func (l *lexer) emit(t token) {
-	l.itemEmitter <- item{typ: t, col: l.startCol, val: string(l.currentValue), line: l.startLine}
+	val := ""
+	if l.isString && !l.isGapped && len(l.currentValue) != 0 {
+		val = l.input[l.valueStart:l.valueEnd]
+	} else if len(l.currentValue) != 0 {
+		val = string(l.currentValue)
+	}
+	l.items = append(l.items, item{typ: t, col: l.startCol, val: val, line: l.startLine, offset: l.startOffset})
 	l.startLine = l.line
 	l.currentValue = l.currentValue[0:0]
+	l.isGapped = false
 	l.startCol = l.col
+	l.startOffset = l.offset
 }
 
-// nextItem returns the next item from the input.
-// Called by the parser, not in the lexing goroutine.
+// getNextItem returns the next item from the input, running the state machine until it emits one.
+// Once the input has been consumed, or an error has been emitted, every call returns itemEOF
 func (l *lexer) getNextItem() item {
-	return <-l.itemEmitter
-}
-
-// drain drains the output so the lexing goroutine will exit.
-// Called by the parser, not in the lexing goroutine.
-func (l *lexer) drain() {
-	for range l.itemEmitter {
-
+	for l.next == len(l.items) {
+		if l.currentState == nil || l.currentState == stateError {
+			return item{typ: itemEOF, line: l.line, col: l.col}
+		}
+		l.items = l.items[:0]
+		l.next = 0
+		l.currentState = l.currentState.parse(l)
 	}
+	next := l.items[l.next]
+	l.next++
+	return next
 }
 
 func initializeStateVariables() {
+	nameExpected := []string{"a name character", "'.'", "'['", "the end of the path"}
+	followExpected := []string{"'.'", "'['", "the end of the path"}
 	stateEnd = &lexerState{}
 	stateError = &lexerState{}
 
-	stateItemVariableName = &lexerState{}
-	stateItemSquareBracketOpen = &lexerState{}
-	stateItemSquareBracketClose = &lexerState{}
-	stateItemMapKey = &lexerState{}
-	stateItemMapKeyEnd = &lexerState{}
-	stateItemArrayIndex = &lexerState{}
-	stateItemDot = &lexerState{}
+	stateItemVariableName = &lexerState{expected: nameExpected}
+	stateItemSquareBracketOpen = &lexerState{expected: []string{"an array index", "a range", `'"'`, "'('", "'*'", "'?('", "a keyed element"}}
+	stateItemSquareBracketClose = &lexerState{expected: followExpected}
+	stateItemMapKey = &lexerState{expected: []string{`'"'`}}
+	stateItemMapKeyEnd = &lexerState{expected: []string{"']'", "','"}}
+	stateItemTypedMapKey = &lexerState{expected: []string{"')'"}}
+	stateItemFilterStart = &lexerState{expected: []string{"'('"}}
+	stateItemKeyedField = &lexerState{expected: []string{"a name character", "'='"}}
+	stateItemFieldUnion = &lexerState{expected: []string{"a name character", "a quoted name", "','", "'}'"}}
+	stateItemKeyedValue = &lexerState{expected: []string{"a quoted string", "a number", "a boolean", "']'", "','"}}
+	stateItemFilter = &lexerState{expected: []string{"')'"}}
+	stateItemArrayIndex = &lexerState{expected: []string{"a digit", "'-'", "':'", "']'", "','"}}
+	stateItemDot = &lexerState{expected: []string{"a name", "a quoted name", "'*'", "'.'", "'{'"}}
+	stateItemWildcard = &lexerState{expected: []string{"'*'"}}
+	stateItemDescendantDot = &lexerState{expected: []string{"'.'"}}
+	stateItemDescendant = &lexerState{expected: nameExpected}
+	stateItemDescendantStart = &lexerState{expected: []string{"a name", "a quoted name"}}
+	stateItemIndexWildcard = &lexerState{expected: []string{"'*'"}}
+	stateItemQuotedName = &lexerState{expected: []string{"\"'\""}}
+	stateItemQuotedDescendant = &lexerState{expected: []string{"\"'\""}}
 
-	stateStart = &lexerState{}
+	stateStart = &lexerState{expected: []string{"a name", "a quoted name", "'['", "'*'", "'..'", "'{'", "the end of the path"}}
 }
 
-func (l *lexer) returnStateError(err error) *lexerState {
-	errorString := []rune(err.Error())
-	l.currentValue = append(l.currentValue, errorString...)
+// newParseError describes a problem at the current position, while lexing the current state
+func (l *lexer) newParseError(message string) *ParseError {
+	e := &ParseError{
+		Offset:  l.offset,
+		Line:    int(l.line),
+		Col:     int(l.col),
+		Message: message,
+	}
+	if l.currentState != nil {
+		e.Expected = l.currentState.expected
+	}
+	return e
+}
+
+// fail emits an itemError for the problem, after which the lexer stops
+func (l *lexer) fail(e *ParseError) *lexerState {
+	l.err = e
+	l.currentValue = append(l.currentValue[:0], []rune(e.Message)...)
+	l.isGapped = true
 	l.emit(itemError)
 	return stateError
 }
 
+func (l *lexer) returnStateError(err error) *lexerState {
+	e := l.newParseError(err.Error())
+	e.Err = err
+	return l.fail(e)
+}
+
 func isAlphaNumeric(r rune) bool {
 	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
 }
@@ -230,6 +432,10 @@ func isNumber(r rune) bool {
 	return unicode.IsDigit(r)
 }
 
+func isQuoteSingle(r rune) bool {
+	return '\'' == r
+}
+
 func isQuoteDouble(r rune) bool {
 	return '"' == r
 }
@@ -243,6 +449,11 @@ func (l *lexer) handleEOFOrError(err error, emitEventIfEOF token) *lexerState {
 		return l.returnStateError(err)
 	}
 	if err == io.EOF {
+		if emitEventIfEOF == itemError {
+			e := l.newParseError("unexpected end of input")
+			e.AtEnd = true
+			return l.fail(e)
+		}
 		l.emit(emitEventIfEOF)
 		return stateEnd
 	}
@@ -250,11 +461,79 @@ func (l *lexer) handleEOFOrError(err error, emitEventIfEOF token) *lexerState {
 }
 
 func (l *lexer) returnErrorUnexpectedRune(r rune) *lexerState {
-	return l.returnStateError(fmt.Errorf("unexpected rune: '%s'", string(r)))
+	e := l.newParseError(fmt.Sprintf("unexpected '%s'", string(r)))
+	e.Unexpected.Set(r)
+	return l.fail(e)
+}
+
+// lexName reads a variable or descendant name and emits it as t once a character that may follow a name is found
+func (l *lexer) lexName(t token) *lexerState {
+	for {
+		r, err := l.peek()
+		if nextState := l.handleEOFOrError(err, t); nextState != nil {
+			return nextState
+		}
+		switch {
+		case '.' == r:
+			l.ignore()
+			l.emit(t)
+			return stateItemDot
+		case '[' == r:
+			l.ignore()
+			l.emit(t)
+			return stateItemSquareBracketOpen
+		case l.options.isWhitespace(r):
+			l.emit(t)
+			return stateItemSquareBracketClose
+		case l.options.isNameRune(r):
+			err = l.accept()
+			if err != nil {
+				return l.returnStateError(err)
+			}
+		default:
+			return l.returnErrorUnexpectedRune(r)
+		}
+	}
+}
+
+// lexQuotedName reads a name written in single quotes, after the opening quote, and emits it as t without the quotes
+// and escapes. A backslash escapes the rune following it
+func (l *lexer) lexQuotedName(t token) *lexerState {
+	isEscaped := false
+	for {
+		r, err := l.peek()
+		if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
+			return nextState
+		}
+		switch {
+		case !isEscaped && isEscapeChar(r):
+			isEscaped = true
+			l.ignore()
+		case !isEscaped && isQuoteSingle(r):
+			l.ignore()
+			l.emit(t)
+			// a quoted name may be followed by whatever may follow a name
+			return stateItemSquareBracketClose
+		default:
+			isEscaped = false
+			err = l.accept()
+			if err != nil {
+				return l.returnStateError(err)
+			}
+		}
+	}
+}
+
+// emitComma emits the comma separating the alternatives of a union within brackets, which are lexed like the first
+func (l *lexer) emitComma() *lexerState {
+	l.ignore()
+	l.emit(itemComma)
+	return stateItemSquareBracketOpen
 }
 
 func linkNextStates() {
 	stateStart.parse = func(l *lexer) *lexerState {
+		l.skipWhitespace()
 		r, err := l.peek()
 		if nextState := l.handleEOFOrError(err, itemEOF); nextState != nil {
 			return nextState
@@ -263,7 +542,18 @@ func linkNextStates() {
 		case '[' == r:
 			l.ignore()
 			return stateItemSquareBracketOpen
-		case isAlphaNumeric(r):
+		case '*' == r:
+			return stateItemWildcard
+		case '.' == r:
+			l.ignore()
+			return stateItemDescendantDot
+		case '{' == r:
+			l.ignore()
+			return stateItemFieldUnion
+		case isQuoteSingle(r):
+			l.ignore()
+			return stateItemQuotedName
+		case l.options.isNameRune(r):
 			return stateItemVariableName
 		default:
 			return l.returnErrorUnexpectedRune(r)
@@ -276,37 +566,70 @@ func linkNextStates() {
 	}
 
 	stateItemVariableName.parse = func(l *lexer) *lexerState {
-		for {
-			r, err := l.peek()
-			if nextState := l.handleEOFOrError(err, itemVariableName); nextState != nil {
-				return nextState
-			}
-			switch {
-			case '.' == r:
-				l.ignore()
-				l.emit(itemVariableName)
-				return stateItemDot
-			case '[' == r:
-				l.ignore()
-				l.emit(itemVariableName)
-				return stateItemSquareBracketOpen
-			case isAlphaNumeric(r):
-				err = l.accept()
-				if err != nil {
-					return l.returnStateError(err)
-				}
-			default:
-				return l.returnErrorUnexpectedRune(r)
-			}
+		return l.lexName(itemVariableName)
+	}
+
+	stateItemDescendant.parse = func(l *lexer) *lexerState {
+		return l.lexName(itemDescendant)
+	}
+
+	stateItemQuotedName.parse = func(l *lexer) *lexerState {
+		return l.lexQuotedName(itemVariableName)
+	}
+
+	stateItemQuotedDescendant.parse = func(l *lexer) *lexerState {
+		return l.lexQuotedName(itemDescendant)
+	}
+
+	// the second dot of a descendant at the start of a path
+	stateItemDescendantDot.parse = func(l *lexer) *lexerState {
+		r, err := l.peek()
+		if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
+			return nextState
+		}
+		if '.' != r {
+			return l.returnErrorUnexpectedRune(r)
+		}
+		l.ignore()
+		return stateItemDescendantStart
+	}
+
+	// the name of a descendant must have at least one character
+	stateItemDescendantStart.parse = func(l *lexer) *lexerState {
+		l.skipWhitespace()
+		r, err := l.peek()
+		if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
+			return nextState
 		}
+		if isQuoteSingle(r) {
+			l.ignore()
+			return stateItemQuotedDescendant
+		}
+		if !l.options.isNameRune(r) {
+			return l.returnErrorUnexpectedRune(r)
+		}
+		return stateItemDescendant
 	}
 
 	stateItemDot.parse = func(l *lexer) *lexerState {
+		skipped := l.skipWhitespace()
 		r, err := l.peek()
 		if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
 			return nextState
 		}
-		if isAlphaNumeric(r) {
+		if '*' == r {
+			return stateItemWildcard
+		} else if '.' == r && !skipped {
+			// the dots of a descendant cannot be separated
+			l.ignore()
+			return stateItemDescendantStart
+		} else if '{' == r {
+			l.ignore()
+			return stateItemFieldUnion
+		} else if isQuoteSingle(r) {
+			l.ignore()
+			return stateItemQuotedName
+		} else if l.options.isNameRune(r) {
 			return stateItemVariableName
 		} else {
 			// invalid character
@@ -315,6 +638,7 @@ func linkNextStates() {
 	}
 
 	stateItemSquareBracketOpen.parse = func(l *lexer) *lexerState {
+		l.skipWhitespace()
 		r, err := l.peek()
 		if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
 			return nextState
@@ -323,7 +647,17 @@ func linkNextStates() {
 		case isQuoteDouble(r):
 			l.ignore()
 			return stateItemMapKey
-		case isNumber(r):
+		case '(' == r:
+			l.ignore()
+			return stateItemTypedMapKey
+		case '*' == r:
+			return stateItemIndexWildcard
+		case '?' == r:
+			l.ignore()
+			return stateItemFilterStart
+		case '_' == r || unicode.IsLetter(r) || isQuoteSingle(r):
+			return stateItemKeyedField
+		case isNumber(r) || '-' == r || ':' == r:
 			return stateItemArrayIndex
 		default:
 			return l.returnErrorUnexpectedRune(r)
@@ -342,13 +676,260 @@ func linkNextStates() {
 				l.emit(itemMapKey)
 				return stateItemMapKeyEnd
 			}
-			if isEscapeChar(r) {
-				if isEscaped {
-					isEscaped = false
-				} else {
-					isEscaped = true
+			// only the rune directly following an unescaped backslash is escaped
+			isEscaped = !isEscaped && isEscapeChar(r)
+			err = l.accept()
+			if nil != err {
+				return l.returnStateError(err)
+			}
+		}
+	}
+
+	stateItemTypedMapKey.parse = func(l *lexer) *lexerState {
+		isQuoted := false
+		isEscaped := false
+		l.skipWhitespace()
+		for {
+			r, err := l.peek()
+			if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
+				return nextState
+			}
+			if !isQuoted && ')' == r {
+				for len(l.currentValue) != 0 && l.options.isWhitespace(l.currentValue[len(l.currentValue)-1]) {
+					l.currentValue = l.currentValue[:len(l.currentValue)-1]
+					l.isGapped = true
+				}
+				l.ignore()
+				l.emit(itemTypedMapKey)
+				return stateItemMapKeyEnd
+			}
+			if !isEscaped && isQuoteDouble(r) {
+				isQuoted = !isQuoted
+			}
+			isEscaped = isQuoted && !isEscaped && isEscapeChar(r)
+			err = l.accept()
+			if nil != err {
+				return l.returnStateError(err)
+			}
+		}
+	}
+
+	// a wildcard is followed by whatever may follow a closed bracket
+	stateItemWildcard.parse = func(l *lexer) *lexerState {
+		l.ignore()
+		l.emit(itemWildcard)
+		return stateItemSquareBracketClose
+	}
+
+	// an index wildcard must be closed like a map key
+	stateItemIndexWildcard.parse = func(l *lexer) *lexerState {
+		l.ignore()
+		l.emit(itemIndexWildcard)
+		return stateItemMapKeyEnd
+	}
+
+	// the field name of a keyed element is kept in the item, up to and including the =. A quoted field name is kept with
+	// its quotes and escapes
+	stateItemKeyedField.parse = func(l *lexer) *lexerState {
+		sawSpace := false
+		isQuoted := false
+		isEscaped := false
+		for {
+			r, err := l.peek()
+			if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
+				return nextState
+			}
+			isAfterQuote := !isQuoted && len(l.currentValue) != 0 && isQuoteSingle(l.currentValue[0])
+			switch {
+			case isQuoted:
+				if !isEscaped && isQuoteSingle(r) {
+					isQuoted = false
+				}
+				isEscaped = !isEscaped && isEscapeChar(r)
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
+			case isQuoteSingle(r) && len(l.currentValue) == 0:
+				isQuoted = true
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
+			case l.options.isWhitespace(r):
+				l.ignore()
+				sawSpace = true
+			case '=' == r:
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
+				return stateItemKeyedValue
+			case !sawSpace && !isAfterQuote && l.options.isNameRune(r):
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
+			default:
+				return l.returnErrorUnexpectedRune(r)
+			}
+		}
+	}
+
+	// the value of a keyed element is either quoted, and may contain anything, or a literal that ends at ]
+	stateItemKeyedValue.parse = func(l *lexer) *lexerState {
+		isQuoted := false
+		isEscaped := false
+		sawSpace := false
+		for {
+			r, err := l.peek()
+			if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
+				return nextState
+			}
+			previous := l.currentValue[len(l.currentValue)-1]
+			if !isQuoted && l.options.isWhitespace(r) {
+				l.ignore()
+				sawSpace = true
+				continue
+			}
+			if sawSpace && '=' != previous && ']' != r && ',' != r {
+				// whitespace may only surround the value
+				return l.returnErrorUnexpectedRune(r)
+			}
+			sawSpace = false
+			switch {
+			case isQuoted:
+				if !isEscaped && isQuoteDouble(r) {
+					isQuoted = false
+				}
+				isEscaped = !isEscaped && isEscapeChar(r)
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
+				if !isQuoted {
+					l.emit(itemKeyed)
+					return stateItemMapKeyEnd
+				}
+			case isQuoteDouble(r) && '=' == previous:
+				isQuoted = true
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
+			case ']' == r && '=' != previous:
+				l.ignore()
+				l.emit(itemKeyed)
+				return stateItemSquareBracketClose
+			case ',' == r && '=' != previous:
+				l.emit(itemKeyed)
+				return l.emitComma()
+			case '-' == r || '.' == r || '+' == r || isAlphaNumeric(r):
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
+			default:
+				return l.returnErrorUnexpectedRune(r)
+			}
+		}
+	}
+
+	// field unions are lexed as a whole, the names are split and unquoted by the parser. Quoted names are kept with
+	// their quotes and escapes, and must be the whole name
+	stateItemFieldUnion.parse = func(l *lexer) *lexerState {
+		isQuoted := false
+		isEscaped := false
+		sawSpace := false
+		for {
+			r, err := l.peek()
+			if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
+				return nextState
+			}
+			previous := rune(0)
+			if len(l.currentValue) != 0 {
+				previous = l.currentValue[len(l.currentValue)-1]
+			}
+			if !isQuoted && l.options.isWhitespace(r) {
+				l.ignore()
+				sawSpace = true
+				continue
+			}
+			if sawSpace && previous != 0 && previous != ',' && r != ',' && r != '}' {
+				// whitespace may only surround the names
+				return l.returnErrorUnexpectedRune(r)
+			}
+			sawSpace = false
+			isAfterQuote := !isQuoted && isQuoteSingle(previous)
+			switch {
+			case isQuoted:
+				if !isEscaped && isQuoteSingle(r) {
+					isQuoted = false
 				}
+				isEscaped = !isEscaped && isEscapeChar(r)
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
+			case '}' == r && len(l.currentValue) != 0:
+				l.ignore()
+				l.emit(itemFieldUnion)
+				return stateItemSquareBracketClose
+			case isQuoteSingle(r) && (previous == 0 || previous == ','):
+				isQuoted = true
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
+			case ',' == r || (!isAfterQuote && l.options.isNameRune(r)):
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
+			default:
+				return l.returnErrorUnexpectedRune(r)
 			}
+		}
+	}
+
+	stateItemFilterStart.parse = func(l *lexer) *lexerState {
+		l.skipWhitespace()
+		r, err := l.peek()
+		if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
+			return nextState
+		}
+		if '(' != r {
+			return l.returnErrorUnexpectedRune(r)
+		}
+		l.ignore()
+		return stateItemFilter
+	}
+
+	// the expression may contain parentheses and strings, so it ends at the first unquoted ) that closes the opening (
+	stateItemFilter.parse = func(l *lexer) *lexerState {
+		depth := 0
+		isQuoted := false
+		isEscaped := false
+		for {
+			r, err := l.peek()
+			if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
+				return nextState
+			}
+			if !isQuoted && ')' == r {
+				if depth == 0 {
+					l.ignore()
+					l.emit(itemFilter)
+					return stateItemMapKeyEnd
+				}
+				depth--
+			}
+			if !isQuoted && '(' == r {
+				depth++
+			}
+			if !isEscaped && isQuoteDouble(r) {
+				isQuoted = !isQuoted
+			}
+			isEscaped = isQuoted && !isEscaped && isEscapeChar(r)
 			err = l.accept()
 			if nil != err {
 				return l.returnStateError(err)
@@ -357,11 +938,14 @@ func linkNextStates() {
 	}
 
 	stateItemMapKeyEnd.parse = func(l *lexer) *lexerState {
+		l.skipWhitespace()
 		r, err := l.peek()
 		if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
 			return nextState
 		}
 		switch {
+		case r == ',':
+			return l.emitComma()
 		case r != ']':
 			return l.returnErrorUnexpectedRune(r)
 		default:
@@ -370,17 +954,53 @@ func linkNextStates() {
 		}
 	}
 
+	// array indexes and ranges share a state as both start with an optional number. Each part of a range is an
+	// optional number, so a part may be empty, but a minus must be followed by a digit
 	stateItemArrayIndex.parse = func(l *lexer) *lexerState {
+		colons := 0
+		sawSpace := false
 		for {
 			r, err := l.peek()
 			if nextState := l.handleEOFOrError(err, itemError); nextState != nil {
 				return nextState
 			}
+			previous := rune(0)
+			if len(l.currentValue) != 0 {
+				previous = l.currentValue[len(l.currentValue)-1]
+			}
+			if l.options.isWhitespace(r) {
+				l.ignore()
+				sawSpace = true
+				continue
+			}
+			if sawSpace && isNumber(r) && (isNumber(previous) || '-' == previous) {
+				// whitespace may not split a number
+				return l.returnErrorUnexpectedRune(r)
+			}
+			sawSpace = false
 			switch {
-			case r == ']':
+			case r == ']' && colons == 0 && isNumber(previous):
 				l.ignore()
 				l.emit(itemArrayIndex)
 				return stateItemSquareBracketClose
+			case r == ',' && colons == 0 && isNumber(previous):
+				l.emit(itemArrayIndex)
+				return l.emitComma()
+			case r == ']' && colons != 0 && previous != '-':
+				l.ignore()
+				l.emit(itemArrayRange)
+				return stateItemSquareBracketClose
+			case r == ':' && colons < 2 && previous != '-':
+				colons++
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
+			case r == '-' && (previous == 0 || previous == ':'):
+				err = l.accept()
+				if err != nil {
+					return l.returnStateError(err)
+				}
 			case isNumber(r):
 				err = l.accept()
 				if err != nil {
@@ -393,6 +1013,7 @@ func linkNextStates() {
 	}
 
 	stateItemSquareBracketClose.parse = func(l *lexer) *lexerState {
+		l.skipWhitespace()
 		r, err := l.peek()
 		if nextState := l.handleEOFOrError(err, itemEOF); nextState != nil {
 			return nextState
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/wojnosystems/go-optional"
 	"testing"
+	"testing/quick"
 )
 
 func TestParse(t *testing.T) {
@@ -43,6 +45,341 @@ func TestParse(t *testing.T) {
 				return r
 			},
 		},
+		"negative arrayIndex": {
+			input: "items[-12]",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("items"))
+				r.Append(NewArrayIndex(-12))
+				return r
+			},
+		},
+		"lone minus": {
+			input: "[-]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"trailing minus": {
+			input: "[1-]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"double minus": {
+			input: "[--1]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"array range": {
+			input: "items[1:3]",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("items"))
+				r.Append(NewArrayRange(optional.NewIntFrom(1), optional.NewIntFrom(3), optional.NewInt()))
+				return r
+			},
+		},
+		"array range step": {
+			input: "items[::2].name",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("items"))
+				r.Append(NewArrayRange(optional.NewInt(), optional.NewInt(), optional.NewIntFrom(2)))
+				r.Append(NewInstanceVariableNamed("name"))
+				return r
+			},
+		},
+		"array range negative": {
+			input: "[-2::-1]",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewArrayRange(optional.NewIntFrom(-2), optional.NewInt(), optional.NewIntFrom(-1)))
+				return r
+			},
+		},
+		"array range everything": {
+			input: "[:]",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewArrayRange(optional.NewInt(), optional.NewInt(), optional.NewInt()))
+				return r
+			},
+		},
+		"array range too many parts": {
+			input: "[1:2:3:4]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"array range zero step": {
+			input: "[::0]",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewArrayRange(optional.NewInt(), optional.NewInt(), optional.NewIntFrom(0)))
+				return r
+			},
+		},
+		"array range lone minus": {
+			input: "[1:-]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"wildcard index": {
+			input: "users[*].email",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("users"))
+				r.Append(NewIndexWildcard())
+				r.Append(NewInstanceVariableNamed("email"))
+				return r
+			},
+		},
+		"wildcard field": {
+			input: "settings.*[0]",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("settings"))
+				r.Append(NewWildcard())
+				r.Append(NewArrayIndex(0))
+				return r
+			},
+		},
+		"wildcard root": {
+			input: "*.name",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewWildcard())
+				r.Append(NewInstanceVariableNamed("name"))
+				return r
+			},
+		},
+		"wildcard within name": {
+			input: "set*",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"unclosed wildcard index": {
+			input: "users[*",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"descendant": {
+			input: "..password",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewDescendantNamed("password"))
+				return r
+			},
+		},
+		"nested descendant": {
+			input: "users[0]..secret.value",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("users"))
+				r.Append(NewArrayIndex(0))
+				r.Append(NewDescendantNamed("secret"))
+				r.Append(NewInstanceVariableNamed("value"))
+				return r
+			},
+		},
+		"descendant after field": {
+			input: "config..password",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("config"))
+				r.Append(NewDescendantNamed("password"))
+				return r
+			},
+		},
+		"three dots": {
+			input: "config...password",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"descendant without name": {
+			input: "config..",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"single leading dot": {
+			input: ".password",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"filter": {
+			input: `users[?(@.age > 21 && (@.name == "a)b" || !@.admin))].email`,
+			expected: func() Pather {
+				filter, err := NewFilter(`@.age > 21 && (@.name == "a)b" || !@.admin)`)
+				if err != nil {
+					panic(err)
+				}
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("users"))
+				r.Append(filter)
+				r.Append(NewInstanceVariableNamed("email"))
+				return r
+			},
+		},
+		"filter without parentheses": {
+			input: "users[?@.age]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"unclosed filter": {
+			input: "users[?(@.age > 1]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"invalid filter expression": {
+			input: "users[?(@.age >)]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"keyed element": {
+			input: `containers[name="web]"].image`,
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("containers"))
+				r.Append(NewKeyedElement("name", "web]"))
+				r.Append(NewInstanceVariableNamed("image"))
+				return r
+			},
+		},
+		"keyed element number": {
+			input: "ports[containerPort=-80][0]",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("ports"))
+				r.Append(NewKeyedElement("containerPort", -80))
+				r.Append(NewArrayIndex(0))
+				return r
+			},
+		},
+		"keyed element without value": {
+			input: "containers[name=]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"keyed element bare word": {
+			input: "containers[name=web]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"keyed element without equals": {
+			input: "containers[name]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"field union": {
+			input: "user.{name,email}.value",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("user"))
+				r.Append(MustNewUnion(NewInstanceVariableNamed("name"), NewInstanceVariableNamed("email")))
+				r.Append(NewInstanceVariableNamed("value"))
+				return r
+			},
+		},
+		"index union": {
+			input: "items[0,3,-7][1,2]",
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("items"))
+				r.Append(MustNewUnion(NewArrayIndex(0), NewArrayIndex(3), NewArrayIndex(-7)))
+				r.Append(MustNewUnion(NewArrayIndex(1), NewArrayIndex(2)))
+				return r
+			},
+		},
+		"map key union": {
+			input: `labels["a","b,c"][0]`,
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewInstanceVariableNamed("labels"))
+				r.Append(MustNewUnion(NewMapKey("a"), NewMapKey("b,c")))
+				r.Append(NewArrayIndex(0))
+				return r
+			},
+		},
+		"mixed union": {
+			input: `[(1),"a",name=2,port="x"]`,
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(MustNewUnion(NewMapKeyOf(1), NewMapKey("a"), NewKeyedElement("name", 2), NewKeyedElement("port", "x")))
+				return r
+			},
+		},
+		"empty field union": {
+			input: "user.{}",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"trailing comma in field union": {
+			input: "user.{name,}",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"unclosed field union": {
+			input: "user.{name",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"wildcard in union": {
+			input: "items[0,*]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"range in union": {
+			input: "items[0,1:2]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
+		"trailing comma in union": {
+			input: "items[0,]",
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
 		"multiple variables": {
 			input: "var1.var2.var3",
 			expected: func() Pather {
@@ -121,6 +458,30 @@ func TestParse(t *testing.T) {
 				return r
 			},
 		},
+		"escaped map key": {
+			input: `["a\"b\\c\nd\u00e9"]`,
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewMapKey("a\"b\\c\nd\u00e9"))
+				return r
+			},
+		},
+		"escaped quote before close": {
+			input: `["a\""].b`,
+			expected: func() Pather {
+				r := NewRoot()
+				r.Append(NewMapKey("a\""))
+				r.Append(NewInstanceVariableNamed("b"))
+				return r
+			},
+		},
+		"invalid escape": {
+			input: `["a\qb"]`,
+			expected: func() Pather {
+				return nil
+			},
+			expectedErr: true,
+		},
 		"interrupted variable": {
 			input: "dogs]good",
 			expected: func() Pather {
@@ -179,6 +540,17 @@ func TestGoPath_String(t *testing.T) {
 				return NewRoot()
 			},
 		},
+		"special map keys": {
+			input: func() Pather {
+				p := NewRoot()
+				p.Append(NewMapKey("quote\"d"))
+				p.Append(NewMapKey("back\\slash\\"))
+				p.Append(NewMapKey("new\nline\ttab"))
+				p.Append(NewMapKey("]["))
+				p.Append(NewMapKey("\x00\u2028"))
+				return p
+			},
+		},
 		"every element": {
 			input: func() Pather {
 				p := NewRoot()
@@ -188,6 +560,65 @@ func TestGoPath_String(t *testing.T) {
 				return p
 			},
 		},
+		"array ranges": {
+			input: func() Pather {
+				p := NewRoot()
+				p.Append(NewInstanceVariableNamed("dogs"))
+				p.Append(NewArrayRange(optional.NewIntFrom(1), optional.NewInt(), optional.NewInt()))
+				p.Append(NewArrayRange(optional.NewInt(), optional.NewIntFrom(-1), optional.NewIntFrom(-2)))
+				p.Append(NewInstanceVariableNamed("name"))
+				return p
+			},
+		},
+		"descendants": {
+			input: func() Pather {
+				p := NewRoot()
+				p.Append(NewDescendantNamed("a"))
+				p.Append(NewArrayIndex(1))
+				p.Append(NewDescendantNamed("b"))
+				p.Append(NewInstanceVariableNamed("c"))
+				return p
+			},
+		},
+		"filters": {
+			input: func() Pather {
+				filter, err := NewFilter(`@["k"] == "v]" && @[0]`)
+				if err != nil {
+					panic(err)
+				}
+				p := NewRoot()
+				p.Append(NewInstanceVariableNamed("a"))
+				p.Append(filter)
+				return p
+			},
+		},
+		"keyed elements": {
+			input: func() Pather {
+				p := NewRoot()
+				p.Append(NewKeyedElement("name", "a\"b"))
+				p.Append(NewKeyedElement("enabled", true))
+				p.Append(NewKeyedElement("ratio", 0.5))
+				return p
+			},
+		},
+		"unions": {
+			input: func() Pather {
+				p := NewRoot()
+				p.Append(MustNewUnion(NewInstanceVariableNamed("a"), NewInstanceVariableNamed("b")))
+				p.Append(MustNewUnion(NewArrayIndex(0), NewMapKey("x"), NewKeyedElement("name", "y")))
+				p.Append(MustNewUnion(NewInstanceVariableNamed("c")))
+				return p
+			},
+		},
+		"wildcards": {
+			input: func() Pather {
+				p := NewRoot()
+				p.Append(NewWildcard())
+				p.Append(NewIndexWildcard())
+				p.Append(NewWildcard())
+				return p
+			},
+		},
 	}
 
 	for caseName, c := range cases {
@@ -199,3 +630,105 @@ func TestGoPath_String(t *testing.T) {
 		assert.True(t, expected.IsEqual(actual), caseName)
 	}
 }
+
+func TestGoPath_StringRoundTrip(t *testing.T) {
+	property := func(keys []string, indexes []uint16) bool {
+		p := NewRoot()
+		p.Append(NewInstanceVariableNamed("root"))
+		for i, key := range keys {
+			p.Append(NewMapKey(key))
+			if i < len(indexes) {
+				p.Append(NewArrayIndex(int(indexes[i])))
+			}
+			p.Append(NewInstanceVariableNamed("field"))
+		}
+		actual, err := Parse(bytes.NewBufferString(p.String()))
+		return err == nil && p.IsEqual(actual)
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestGoPath_StringRoundTripConstructors(t *testing.T) {
+	filter, err := NewFilter(`@.age > 21 && @.'first name' == "a"`)
+	require.NoError(t, err)
+	components := map[string]Componenter{
+		"field":                NewInstanceVariableNamed("name"),
+		"field leading digit":  NewInstanceVariableNamed("9abc"),
+		"field empty":          NewInstanceVariableNamed(""),
+		"field hyphen":         NewInstanceVariableNamed("my-field"),
+		"field quote":          NewInstanceVariableNamed(`it's a\b`),
+		"index":                NewArrayIndex(3),
+		"negative index":       NewArrayIndex(-1),
+		"map key":              NewMapKey(`a"b\c`),
+		"map key empty":        NewMapKey(""),
+		"typed int key":        NewMapKeyOf(-42),
+		"typed float key":      NewMapKeyOf(1.5),
+		"typed bool key":       NewMapKeyOf(true),
+		"typed text key":       NewMapKeyOf(testColorBlue),
+		"range":                NewArrayRange(optional.NewIntFrom(1), optional.NewIntFrom(-1), optional.NewInt()),
+		"range step":           NewArrayRange(optional.NewInt(), optional.NewInt(), optional.NewIntFrom(-2)),
+		"range zero step":      NewArrayRange(optional.NewInt(), optional.NewInt(), optional.NewIntFrom(0)),
+		"wildcard":             NewWildcard(),
+		"index wildcard":       NewIndexWildcard(),
+		"descendant":           NewDescendantNamed("name"),
+		"descendant hyphen":    NewDescendantNamed("content-type"),
+		"descendant empty":     NewDescendantNamed(""),
+		"filter":               filter,
+		"keyed":                NewKeyedElement("host", "web"),
+		"keyed number":         NewKeyedElement("port", 80),
+		"keyed hyphen":         NewKeyedElement("my-field", "x"),
+		"keyed leading digit":  NewKeyedElement("9a", `"quoted"`),
+		"keyed equals":         NewKeyedElement("a=b", true),
+		"field union":          MustNewUnion(NewInstanceVariableNamed("name"), NewInstanceVariableNamed("e-mail,x")),
+		"index union":          MustNewUnion(NewArrayIndex(0), NewArrayIndex(-2)),
+		"key union":            MustNewUnion(NewMapKey("a"), NewMapKeyOf(1), NewKeyedElement("my-field", "x")),
+		"single field union":   MustNewUnion(NewInstanceVariableNamed("")),
+		"single keyed union":   MustNewUnion(NewKeyedElement("host", "web")),
+		"single map key union": MustNewUnion(NewMapKey("]")),
+		"single index union":   MustNewUnion(NewArrayIndex(2)),
+	}
+
+	for caseName, component := range components {
+		for _, p := range []Pather{New(component), New(NewInstanceVariableNamed("a"), component, NewInstanceVariableNamed("b"))} {
+			actual, err := ParseString(p.String())
+			require.NoError(t, err, "%s: %s", caseName, p.String())
+			assert.True(t, p.IsEqual(actual), "%s: %s parsed as %s", caseName, p.String(), actual.String())
+		}
+	}
+
+	property := func(field, key, descendant, keyField, keyValue string) bool {
+		p := New(
+			NewInstanceVariableNamed(field),
+			NewMapKey(key),
+			NewDescendantNamed(descendant),
+			NewKeyedElement(keyField, keyValue),
+			MustNewUnion(NewInstanceVariableNamed(field), NewInstanceVariableNamed(keyField)),
+		)
+		actual, err := ParseString(p.String())
+		return err == nil && p.IsEqual(actual)
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestNewUnion_Invalid(t *testing.T) {
+	cases := map[string][]Componenter{
+		"empty":                  {},
+		"field then index":       {NewInstanceVariableNamed("a"), NewArrayIndex(0)},
+		"index then field":       {NewArrayIndex(0), NewInstanceVariableNamed("a")},
+		"unsupported components": {NewWildcard()},
+	}
+
+	for caseName, alternatives := range cases {
+		union, err := NewUnion(alternatives...)
+		assert.Error(t, err, caseName)
+		assert.Nil(t, union, caseName)
+		assert.Panics(t, func() {
+			MustNewUnion(alternatives...)
+		}, caseName)
+	}
+}
+
+func TestNewUnion_Single(t *testing.T) {
+	assert.True(t, MustNewUnion(NewArrayIndex(2)).IsEqual(NewArrayIndex(2)))
+	assert.Equal(t, ComponentKindUnion, MustNewUnion(NewInstanceVariableNamed("a")).Kind())
+}
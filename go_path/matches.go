@@ -0,0 +1,44 @@
+package go_path
+
+// componentMatcher is implemented by components that can decide whether they select a concrete component without
+// the value the path is resolved against
+type componentMatcher interface {
+	Componenter
+	// matchesComponent is true if the component selects the child that component locates
+	matchesComponent(component Componenter) bool
+}
+
+// Matches is true if the concrete path p is one of the paths that pattern could select.
+// Concrete components in pattern must be equal to the component of p at the same position. Wildcards match any single
+// field, index or map key. Descendants match any number of components ending in a field or string map key with their
+// name. Ranges match the indexes they select; ranges with a negative bound or step depend on the length of the slice,
+// so they match any index that they could select from a slice of some length. Filters depend on the value, so they
+// match any index or map key.
+func Matches(pattern Pather, p Pather) bool {
+	return matchComponents(components(pattern), components(p))
+}
+
+func matchComponents(pattern []Componenter, concrete []Componenter) bool {
+	if len(pattern) == 0 {
+		return len(concrete) == 0
+	}
+	if descendant, ok := pattern[0].(*pathDescendant); ok {
+		for i, component := range concrete {
+			if descendant.selects(component) && matchComponents(pattern[1:], concrete[i+1:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(concrete) == 0 {
+		return false
+	}
+	if matcher, ok := pattern[0].(componentMatcher); ok {
+		if !matcher.matchesComponent(concrete[0]) {
+			return false
+		}
+	} else if !pattern[0].IsEqual(concrete[0]) {
+		return false
+	}
+	return matchComponents(pattern[1:], concrete[1:])
+}
@@ -0,0 +1,107 @@
+package go_path
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMatches(t *testing.T) {
+	cases := map[string]struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		"concrete": {
+			pattern:  `users[0]["x"]`,
+			path:     `users[0]["x"]`,
+			expected: true,
+		},
+		"concrete differs": {
+			pattern: `users[0]`,
+			path:    `users[1]`,
+		},
+		"wildcard index": {
+			pattern:  "users[*].email",
+			path:     "users[3].email",
+			expected: true,
+		},
+		"wildcard map key": {
+			pattern:  "labels[*]",
+			path:     `labels["env"]`,
+			expected: true,
+		},
+		"wildcard field": {
+			pattern:  "settings.*",
+			path:     "settings.theme",
+			expected: true,
+		},
+		"wildcard is a single component": {
+			pattern: "settings.*",
+			path:    "settings.theme.color",
+		},
+		"descendant": {
+			pattern:  "..password",
+			path:     `users[2].credentials["password"]`,
+			expected: true,
+		},
+		"descendant at root": {
+			pattern:  "..password",
+			path:     "password",
+			expected: true,
+		},
+		"descendant needs the name last": {
+			pattern: "..password",
+			path:    "password.hash",
+		},
+		"descendant then field": {
+			pattern:  "..password.hash",
+			path:     "a.password.b.password.hash",
+			expected: true,
+		},
+		"descendant does not match indexes": {
+			pattern: "..password",
+			path:    "users[0]",
+		},
+		"range": {
+			pattern:  "items[1:7:3]",
+			path:     "items[4]",
+			expected: true,
+		},
+		"range step": {
+			pattern: "items[1:7:3]",
+			path:    "items[5]",
+		},
+		"range end": {
+			pattern: "items[1:7:3]",
+			path:    "items[7]",
+		},
+		"range from end": {
+			pattern:  "items[-2:]",
+			path:     "items[40]",
+			expected: true,
+		},
+		"union": {
+			pattern:  "user.{name,email}",
+			path:     "user.email",
+			expected: true,
+		},
+		"union other": {
+			pattern: "user.{name,email}",
+			path:    "user.phone",
+		},
+		"too short": {
+			pattern: "users[*].email",
+			path:    "users[0]",
+		},
+	}
+
+	for caseName, c := range cases {
+		pattern, err := Parse(bytes.NewBufferString(c.pattern))
+		require.NoError(t, err, caseName)
+		p, err := Parse(bytes.NewBufferString(c.path))
+		require.NoError(t, err, caseName)
+		assert.Equal(t, c.expected, Matches(pattern, p), caseName)
+	}
+}
@@ -0,0 +1,28 @@
+package go_path
+
+// ParseString parses a path from a string, see Parse. The values of the path's components share memory with input,
+// so it is cheaper than Parse
+func ParseString(input string) (Pather, error) {
+	lex := newStringLexer(input, ParseOptions{})
+	defer lex.release()
+	return parse(lex, ParseOptions{})
+}
+
+// ParseBytes parses a path from a byte slice, see ParseString
+func ParseBytes(input []byte) (Pather, error) {
+	return ParseString(string(input))
+}
+
+// MustParse parses a path from a string and panics if it is invalid. It is intended for paths known when the program
+// is written, such as package-level variables:
+//
+//	var ownerName = go_path.MustParse("dogs[0].owner.name")
+//
+// The returned path cannot be modified, see Freeze
+func MustParse(input string) Pather {
+	p, err := ParseString(input)
+	if err != nil {
+		panic("go_path: MustParse(" + input + "): " + err.Error())
+	}
+	return Freeze(p)
+}
@@ -0,0 +1,89 @@
+package go_path
+
+import (
+	"strings"
+	"testing"
+)
+
+var benchmarkPaths = map[string]string{
+	"field":   "name",
+	"typical": `dogs[5].attributes["fur"].color`,
+	"long":    `organization.teams[12].members["ada"].roles[-1].permissions[(42)].scopes[0].name`,
+	"pattern": `users[?(@.age > 21)].{name,email}`,
+}
+
+func BenchmarkParse(b *testing.B) {
+	for name, path := range benchmarkPaths {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := Parse(strings.NewReader(path)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseString(b *testing.B) {
+	for name, path := range benchmarkPaths {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ParseString(path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseCache(b *testing.B) {
+	cache := NewParseCache(len(benchmarkPaths))
+	for name, path := range benchmarkPaths {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := cache.Parse(path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLexer(b *testing.B) {
+	for name, path := range benchmarkPaths {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			reader := strings.NewReader(path)
+			for i := 0; i < b.N; i++ {
+				reader.Reset(path)
+				lex := newLexer(reader, ParseOptions{})
+				for next := lex.getNextItem(); next.typ != itemEOF; next = lex.getNextItem() {
+					if next.typ == itemError {
+						b.Fatal(next.val)
+					}
+				}
+				lex.release()
+			}
+		})
+	}
+}
+
+func BenchmarkLexerString(b *testing.B) {
+	for name, path := range benchmarkPaths {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				lex := newStringLexer(path, ParseOptions{})
+				for next := lex.getNextItem(); next.typ != itemEOF; next = lex.getNextItem() {
+					if next.typ == itemError {
+						b.Fatal(next.val)
+					}
+				}
+				lex.release()
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package go_path
+
+import (
+	"container/list"
+	"sync"
+)
+
+// ParseCache parses paths, remembering the most recently used ones so that inputs parsed again are not lexed again.
+// Paths are returned frozen, see Freeze, so callers may share them. Inputs that fail to parse are not remembered.
+// A ParseCache is safe for concurrent use.
+type ParseCache struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	// recent orders the entries from most to least recently used
+	recent *list.List
+}
+
+type parseCacheEntry struct {
+	input string
+	path  Pather
+}
+
+// NewParseCache creates a cache that remembers up to capacity paths, forgetting the least recently used path when full
+// A capacity below 1 remembers nothing
+func NewParseCache(capacity int) *ParseCache {
+	return &ParseCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		recent:   list.New(),
+	}
+}
+
+// Parse returns the path that input was last parsed into, or parses it with ParseString and remembers it
+func (c *ParseCache) Parse(input string) (Pather, error) {
+	c.mutex.Lock()
+	if element, ok := c.entries[input]; ok {
+		c.recent.MoveToFront(element)
+		c.mutex.Unlock()
+		return element.Value.(*parseCacheEntry).path, nil
+	}
+	c.mutex.Unlock()
+
+	// parse without holding the lock, so other inputs are not held up
+	p, err := ParseString(input)
+	if err != nil {
+		return nil, err
+	}
+	frozen := Freeze(p)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[input]; ok {
+		// parsed concurrently by another caller
+		c.recent.MoveToFront(element)
+		return element.Value.(*parseCacheEntry).path, nil
+	}
+	if c.capacity < 1 {
+		return frozen, nil
+	}
+	if c.recent.Len() >= c.capacity {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.entries, oldest.Value.(*parseCacheEntry).input)
+	}
+	c.entries[input] = c.recent.PushFront(&parseCacheEntry{input: input, path: frozen})
+	return frozen, nil
+}
+
+// Len is the number of paths remembered
+func (c *ParseCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.recent.Len()
+}
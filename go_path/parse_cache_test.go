@@ -0,0 +1,64 @@
+package go_path
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestParseCache(t *testing.T) {
+	cache := NewParseCache(2)
+	first, err := cache.Parse("a.b")
+	require.NoError(t, err)
+	again, err := cache.Parse("a.b")
+	require.NoError(t, err)
+	assert.True(t, first == again, "remembered paths are shared")
+	_, isMutable := first.(PathMutator)
+	assert.False(t, isMutable)
+
+	_, err = cache.Parse("c")
+	require.NoError(t, err)
+	// a.b is now the most recently used, so d forgets c
+	_, err = cache.Parse("a.b")
+	require.NoError(t, err)
+	_, err = cache.Parse("d")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cache.Len())
+	again, err = cache.Parse("a.b")
+	require.NoError(t, err)
+	assert.True(t, first == again)
+
+	_, err = cache.Parse("e[")
+	assert.Error(t, err)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestParseCache_NoCapacity(t *testing.T) {
+	cache := NewParseCache(0)
+	p, err := cache.Parse("a.b")
+	require.NoError(t, err)
+	assert.Equal(t, "a.b", p.String())
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestParseCache_Concurrent(t *testing.T) {
+	cache := NewParseCache(8)
+	wg := sync.WaitGroup{}
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				input := "rules[" + strconv.Itoa((worker+i)%16) + "].name"
+				p, err := cache.Parse(input)
+				if assert.NoError(t, err) {
+					assert.Equal(t, input, p.String())
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+	assert.Equal(t, 8, cache.Len())
+}
@@ -0,0 +1,70 @@
+package go_path
+
+import (
+	"fmt"
+	"github.com/wojnosystems/go-optional"
+	"strings"
+)
+
+// ParseError is returned by Parse, and the functions built on it, when the input is not a valid path.
+// Use errors.As to retrieve it
+type ParseError struct {
+	// Offset of the problem in the input, in bytes
+	Offset int
+	// Line of the problem, starting at 1
+	Line int
+	// Col is the column of the problem within Line, in runes, starting at 1
+	Col int
+	// Unexpected is the rune that could not be lexed, unset if the problem is not a single rune, such as an invalid
+	// array index, or the end of the input
+	Unexpected optional.Rune
+	// AtEnd is true if the input ended where more was expected
+	AtEnd bool
+	// Expected describes the tokens that would have been valid instead of Unexpected, if known
+	Expected []string
+	// Message describes the problem
+	Message string
+	// Err is the underlying error, if any, such as an error reading the input
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	s := fmt.Sprintf("error parsing: %s at line %d, column %d", e.Message, e.Line, e.Col)
+	if len(e.Expected) != 0 {
+		s += ", expected " + strings.Join(e.Expected, ", ")
+	}
+	return s
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Render returns the line of input that the error is on, followed by a line with a caret under the problem:
+//
+//	dogs[5.name
+//	      ^
+//
+// input must be the input that was parsed
+func (e *ParseError) Render(input string) string {
+	lineStart := strings.LastIndex(input[:minInt(e.Offset, len(input))], "\n") + 1
+	lineEnd := strings.Index(input[lineStart:], "\n")
+	if lineEnd == -1 {
+		lineEnd = len(input)
+	} else {
+		lineEnd += lineStart
+	}
+	sb := strings.Builder{}
+	sb.WriteString(input[lineStart:lineEnd])
+	sb.WriteString("\n")
+	// tabs are kept so that the caret lines up however wide they are displayed
+	for _, r := range input[lineStart:minInt(maxInt(e.Offset, lineStart), lineEnd)] {
+		if r == '\t' {
+			sb.WriteRune('\t')
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+	sb.WriteString("^")
+	return sb.String()
+}
@@ -0,0 +1,109 @@
+package go_path
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	cases := map[string]struct {
+		input      string
+		offset     int
+		line       int
+		col        int
+		unexpected rune
+		atEnd      bool
+		expected   []string
+		rendered   string
+	}{
+		"unexpected rune": {
+			input:      "dogs]good",
+			offset:     4,
+			line:       1,
+			col:        5,
+			unexpected: ']',
+			expected:   []string{"a name character", "'.'", "'['", "the end of the path"},
+			rendered:   "dogs]good\n    ^",
+		},
+		"end of input": {
+			input:    "dogs[",
+			offset:   5,
+			line:     1,
+			col:      6,
+			atEnd:    true,
+			expected: []string{"an array index", "a range", `'"'`, "'('", "'*'", "'?('", "a keyed element"},
+			rendered: "dogs[\n     ^",
+		},
+		"inside array index": {
+			input:      "dogs[5.name",
+			offset:     6,
+			line:       1,
+			col:        7,
+			unexpected: '.',
+			expected:   []string{"a digit", "'-'", "':'", "']'", "','"},
+			rendered:   "dogs[5.name\n      ^",
+		},
+		"invalid literal": {
+			input:    "dogs[99999999999999999999]",
+			offset:   5,
+			line:     1,
+			col:      6,
+			rendered: "dogs[99999999999999999999]\n     ^",
+		},
+		"filter expression": {
+			input:    "users[?(@.age >)]",
+			offset:   15,
+			line:     1,
+			col:      16,
+			rendered: "users[?(@.age >)]\n               ^",
+		},
+		"invalid union": {
+			input:    "items[0,*]",
+			offset:   6,
+			line:     1,
+			col:      7,
+			rendered: "items[0,*]\n      ^",
+		},
+		"second line": {
+			input:      "a[?(@.b ==\n1)].c]",
+			offset:     16,
+			line:       2,
+			col:        6,
+			unexpected: ']',
+			expected:   []string{"a name character", "'.'", "'['", "the end of the path"},
+			rendered:   "1)].c]\n     ^",
+		},
+		"multi-byte runes": {
+			input:      "ü]",
+			offset:     2,
+			line:       1,
+			col:        2,
+			unexpected: ']',
+			expected:   []string{"a name character", "'.'", "'['", "the end of the path"},
+			rendered:   "ü]\n ^",
+		},
+	}
+
+	for caseName, c := range cases {
+		_, err := ParseString(c.input)
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr), caseName)
+		assert.Equal(t, c.offset, parseErr.Offset, caseName)
+		assert.Equal(t, c.line, parseErr.Line, caseName)
+		assert.Equal(t, c.col, parseErr.Col, caseName)
+		assert.Equal(t, c.unexpected != 0, parseErr.Unexpected.IsSet(), caseName)
+		if c.unexpected != 0 {
+			assert.Equal(t, c.unexpected, parseErr.Unexpected.Value(), caseName)
+		}
+		assert.Equal(t, c.atEnd, parseErr.AtEnd, caseName)
+		assert.Equal(t, c.expected, parseErr.Expected, caseName)
+		assert.Equal(t, c.rendered, parseErr.Render(c.input), caseName)
+	}
+}
+
+func TestParseError_Error(t *testing.T) {
+	_, err := ParseString("dogs[5.name")
+	assert.EqualError(t, err, `error parsing: unexpected '.' at line 1, column 7, expected a digit, '-', ':', ']', ','`)
+}
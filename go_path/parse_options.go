@@ -0,0 +1,188 @@
+package go_path
+
+import (
+	"fmt"
+	"github.com/wojnosystems/go-optional"
+	"strings"
+	"unicode"
+)
+
+// IdentifierMode selects which field names Parse accepts
+type IdentifierMode uint8
+
+const (
+	// IdentifierModeDefault accepts names made of letters, digits and underscores, in any order
+	IdentifierModeDefault IdentifierMode = iota
+	// IdentifierModeGo only accepts names that are Go identifiers: a letter or underscore followed by letters, digits
+	// and underscores
+	IdentifierModeGo
+	// IdentifierModeGoExported only accepts Go identifiers that are exported, that is, that start with an upper case
+	// letter
+	IdentifierModeGoExported
+	// IdentifierModeLenient accepts any name, for paths that address JSON or YAML names instead of Go names.
+	// Names may contain anything but whitespace and the runes that separate components: . [ ] { } ' " * ,
+	// Names containing those runes can still be written in single quotes: .'my field'
+	// In the relative paths of filter expressions, names also end at the runes of operators: = ! < > & | ( )
+	IdentifierModeLenient
+)
+
+// ParseOptions configure ParseWithOptions. The zero value parses like Parse.
+// Paths read from untrusted input should set the limits, so that they cannot make the parser, or the code using the
+// path, allocate large amounts of memory
+type ParseOptions struct {
+	// Identifiers selects which field names are accepted
+	Identifiers IdentifierMode
+	// AllowWhitespace accepts whitespace around the tokens of a path, as in "dogs [ 0 ] . name". Whitespace within names
+	// and numbers is still an error, unless it is quoted
+	AllowWhitespace bool
+	// MaxLength is the maximum length of the input in bytes, or 0 for no limit. Input past the limit is not read.
+	// Longer input fails with a LengthLimitError
+	MaxLength int
+	// MaxComponents is the maximum number of components in the path, or 0 for no limit. A union counts as a single
+	// component. The relative paths of filters are limited separately. Longer paths fail with a ComponentLimitError
+	MaxComponents int
+	// MaxArrayIndex is the maximum array index, or 0 for no limit. It applies to the indexes of unions, the bounds of
+	// ranges and the indexes in the relative paths of filters as well, and to negative indexes by their absolute value.
+	// Larger indexes fail with an ArrayIndexLimitError
+	MaxArrayIndex int
+}
+
+// LengthLimitError is the Err of the ParseError returned when the input is longer than ParseOptions.MaxLength
+type LengthLimitError struct {
+	Limit int
+}
+
+func (e *LengthLimitError) Error() string {
+	return fmt.Sprintf("path is longer than %d bytes", e.Limit)
+}
+
+// ComponentLimitError is the Err of the ParseError returned when a path has more than ParseOptions.MaxComponents
+type ComponentLimitError struct {
+	Limit int
+}
+
+func (e *ComponentLimitError) Error() string {
+	return fmt.Sprintf("path has more than %d components", e.Limit)
+}
+
+// ArrayIndexLimitError is the Err of the ParseError returned when an array index is larger than
+// ParseOptions.MaxArrayIndex
+type ArrayIndexLimitError struct {
+	Index int
+	Limit int
+}
+
+func (e *ArrayIndexLimitError) Error() string {
+	return fmt.Sprintf("array index %d is larger than %d", e.Index, e.Limit)
+}
+
+// isWhitespace is true if r is whitespace that may be ignored
+func (o ParseOptions) isWhitespace(r rune) bool {
+	return o.AllowWhitespace && unicode.IsSpace(r)
+}
+
+// isNameRune is true if r may be part of an unquoted field name
+func (o ParseOptions) isNameRune(r rune) bool {
+	if o.Identifiers == IdentifierModeLenient {
+		return !unicode.IsSpace(r) && !unicode.IsControl(r) && !strings.ContainsRune(".[]{}'\"*,", r)
+	}
+	return isAlphaNumeric(r)
+}
+
+// validateComponent checks that the field names of a parsed component are allowed by the identifier mode, and that
+// its indexes are within the limit
+func (o ParseOptions) validateComponent(component Componenter) error {
+	switch c := component.(type) {
+	case *pathStructInstanceVariable:
+		return o.validateName(c.variableName)
+	case *pathDescendant:
+		return o.validateName(c.name)
+	case *pathArrayInstanceVariable:
+		return o.validateIndex(c.index)
+	case *pathArrayRange:
+		for _, bound := range []optional.Int{c.start, c.end} {
+			if bound.IsSet() {
+				if err := o.validateIndex(bound.Value()); err != nil {
+					return err
+				}
+			}
+		}
+	case *pathUnion:
+		for _, alternative := range c.alternatives {
+			if err := o.validateComponent(alternative); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (o ParseOptions) validateIndex(index int) error {
+	// negating the index would overflow for the smallest int
+	if o.MaxArrayIndex > 0 && (index > o.MaxArrayIndex || index < -o.MaxArrayIndex) {
+		return &ArrayIndexLimitError{Index: index, Limit: o.MaxArrayIndex}
+	}
+	return nil
+}
+
+// validateName checks that a field name, quoted or not, is allowed by the identifier mode
+func (o ParseOptions) validateName(name string) error {
+	switch o.Identifiers {
+	case IdentifierModeGo, IdentifierModeGoExported:
+		if !isGoIdentifier(name) {
+			return fmt.Errorf("%s is not a Go identifier", quoteFieldName(name))
+		}
+		if o.Identifiers == IdentifierModeGoExported && !unicode.IsUpper([]rune(name)[0]) {
+			return fmt.Errorf("%s is not exported", quoteFieldName(name))
+		}
+	}
+	return nil
+}
+
+func isGoIdentifier(name string) bool {
+	for i, r := range name {
+		if !isAlphaNumeric(r) || (i == 0 && unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// isPlainName is true if the name can be written without quotes in every identifier mode
+func isPlainName(name string) bool {
+	for _, r := range name {
+		if !isAlphaNumeric(r) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// quoteFieldName returns the name as it is written in a path: as it is if it only has letters, digits and
+// underscores, otherwise in single quotes with backslashes and single quotes escaped
+func quoteFieldName(name string) string {
+	if isPlainName(name) {
+		return name
+	}
+	return quoteName(name)
+}
+
+// quoteName returns the name in single quotes, with backslashes and single quotes escaped
+func quoteName(name string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(name) + "'"
+}
+
+// unquoteFieldName reverses quoteFieldName for the text between the quotes: a backslash escapes the rune after it
+func unquoteFieldName(quoted string) string {
+	sb := strings.Builder{}
+	isEscaped := false
+	for _, r := range quoted {
+		if !isEscaped && isEscapeChar(r) {
+			isEscaped = true
+			continue
+		}
+		isEscaped = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
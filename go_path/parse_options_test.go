@@ -0,0 +1,403 @@
+package go_path
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wojnosystems/go-optional"
+	"strings"
+	"testing"
+)
+
+func TestParseWithOptions_Identifiers(t *testing.T) {
+	type result struct {
+		valid    bool
+		expected Pather
+	}
+	cases := map[string]struct {
+		input   string
+		results map[IdentifierMode]result
+	}{
+		"go identifier": {
+			input: "Dogs.name_2",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault:    {valid: true, expected: New(NewInstanceVariableNamed("Dogs"), NewInstanceVariableNamed("name_2"))},
+				IdentifierModeGo:         {valid: true, expected: New(NewInstanceVariableNamed("Dogs"), NewInstanceVariableNamed("name_2"))},
+				IdentifierModeGoExported: {valid: false},
+				IdentifierModeLenient:    {valid: true, expected: New(NewInstanceVariableNamed("Dogs"), NewInstanceVariableNamed("name_2"))},
+			},
+		},
+		"exported identifiers": {
+			input: "Dogs[0].Name",
+			results: map[IdentifierMode]result{
+				IdentifierModeGo:         {valid: true, expected: New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(0), NewInstanceVariableNamed("Name"))},
+				IdentifierModeGoExported: {valid: true, expected: New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(0), NewInstanceVariableNamed("Name"))},
+			},
+		},
+		"leading digit": {
+			input: "dogs.9abc",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault:    {valid: true, expected: New(NewInstanceVariableNamed("dogs"), NewInstanceVariableNamed("9abc"))},
+				IdentifierModeGo:         {valid: false},
+				IdentifierModeGoExported: {valid: false},
+				IdentifierModeLenient:    {valid: true, expected: New(NewInstanceVariableNamed("dogs"), NewInstanceVariableNamed("9abc"))},
+			},
+		},
+		"unicode letters": {
+			input: "Über.größe",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: true, expected: New(NewInstanceVariableNamed("Über"), NewInstanceVariableNamed("größe"))},
+				IdentifierModeGo:      {valid: true, expected: New(NewInstanceVariableNamed("Über"), NewInstanceVariableNamed("größe"))},
+			},
+		},
+		"hyphen": {
+			input: "my-field.value",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: false},
+				IdentifierModeGo:      {valid: false},
+				IdentifierModeLenient: {valid: true, expected: New(NewInstanceVariableNamed("my-field"), NewInstanceVariableNamed("value"))},
+			},
+		},
+		"quoted": {
+			input: "'my-field'.'first name'",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: true, expected: New(NewInstanceVariableNamed("my-field"), NewInstanceVariableNamed("first name"))},
+				IdentifierModeGo:      {valid: false},
+				IdentifierModeLenient: {valid: true, expected: New(NewInstanceVariableNamed("my-field"), NewInstanceVariableNamed("first name"))},
+			},
+		},
+		"quoted go identifier": {
+			input: "'Name'",
+			results: map[IdentifierMode]result{
+				IdentifierModeGoExported: {valid: true, expected: New(NewInstanceVariableNamed("Name"))},
+			},
+		},
+		"quoted with escapes": {
+			input: `dogs.'it\'s a\\b'[0]`,
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: true, expected: New(NewInstanceVariableNamed("dogs"), NewInstanceVariableNamed(`it's a\b`), NewArrayIndex(0))},
+			},
+		},
+		"empty quoted": {
+			input: "dogs.''",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: true, expected: New(NewInstanceVariableNamed("dogs"), NewInstanceVariableNamed(""))},
+				IdentifierModeGo:      {valid: false},
+				IdentifierModeLenient: {valid: true, expected: New(NewInstanceVariableNamed("dogs"), NewInstanceVariableNamed(""))},
+			},
+		},
+		"unterminated quoted": {
+			input: "dogs.'name",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: false},
+			},
+		},
+		"quoted followed by name": {
+			input: "'dogs'name",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: false},
+			},
+		},
+		"descendant": {
+			input: "..content-type",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: false},
+				IdentifierModeLenient: {valid: true, expected: New(NewDescendantNamed("content-type"))},
+			},
+		},
+		"quoted descendant": {
+			input: "headers..'content-type'",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault:    {valid: true, expected: New(NewInstanceVariableNamed("headers"), NewDescendantNamed("content-type"))},
+				IdentifierModeGoExported: {valid: false},
+			},
+		},
+		"field union": {
+			input: "{first-name,last_name}",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: false},
+				IdentifierModeLenient: {valid: true, expected: New(MustNewUnion(NewInstanceVariableNamed("first-name"), NewInstanceVariableNamed("last_name")))},
+			},
+		},
+		"field union with quoted names": {
+			input: "user.{'a,b','c\\'d',e}",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: true, expected: New(NewInstanceVariableNamed("user"), MustNewUnion(NewInstanceVariableNamed("a,b"), NewInstanceVariableNamed("c'd"), NewInstanceVariableNamed("e")))},
+				IdentifierModeGo:      {valid: false},
+			},
+		},
+		"field union with unexported name": {
+			input: "{Name,email}",
+			results: map[IdentifierMode]result{
+				IdentifierModeGo:         {valid: true, expected: New(MustNewUnion(NewInstanceVariableNamed("Name"), NewInstanceVariableNamed("email")))},
+				IdentifierModeGoExported: {valid: false},
+			},
+		},
+		"filter with unexported field": {
+			input: "A[?(@.x > 1)]",
+			results: map[IdentifierMode]result{
+				IdentifierModeGo:         {valid: true},
+				IdentifierModeGoExported: {valid: false},
+			},
+		},
+		"filter with lenient field": {
+			input: "a[?(@.my-field>1 && @.b-c)]",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: false},
+				IdentifierModeLenient: {valid: true},
+			},
+		},
+		"filter with quoted field": {
+			input: "users[?(@.'e-mail' == \"a\")]",
+			results: map[IdentifierMode]result{
+				IdentifierModeDefault: {valid: true},
+			},
+		},
+	}
+
+	for caseName, c := range cases {
+		for mode, expected := range c.results {
+			actual, err := ParseWithOptions(strings.NewReader(c.input), ParseOptions{Identifiers: mode})
+			if !expected.valid {
+				assert.Error(t, err, caseName)
+				continue
+			}
+			require.NoError(t, err, caseName)
+			if expected.expected != nil {
+				assert.True(t, expected.expected.IsEqual(actual), "%s: mode %d: got %s", caseName, mode, actual)
+			}
+		}
+	}
+}
+
+func TestParseWithOptions_ErrorPosition(t *testing.T) {
+	_, err := ParseWithOptions(strings.NewReader("dogs[0].9abc"), ParseOptions{Identifiers: IdentifierModeGo})
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 9, parseErr.Col)
+	assert.Contains(t, parseErr.Message, "not a Go identifier")
+}
+
+func TestQuotedFieldName_RoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		path     Pather
+		expected string
+	}{
+		"plain": {
+			path:     New(NewInstanceVariableNamed("name")),
+			expected: "name",
+		},
+		"hyphen": {
+			path:     New(NewInstanceVariableNamed("dogs"), NewInstanceVariableNamed("my-field")),
+			expected: "dogs.'my-field'",
+		},
+		"escapes": {
+			path:     New(NewInstanceVariableNamed(`it's a\b`)),
+			expected: `'it\'s a\\b'`,
+		},
+		"descendant": {
+			path:     New(NewDescendantNamed("content type")),
+			expected: "..'content type'",
+		},
+		"field union": {
+			path:     New(MustNewUnion(NewInstanceVariableNamed("a,b"), NewInstanceVariableNamed("c"))),
+			expected: "{'a,b',c}",
+		},
+	}
+
+	for caseName, c := range cases {
+		assert.Equal(t, c.expected, c.path.String(), caseName)
+		actual, err := ParseString(c.path.String())
+		require.NoError(t, err, caseName)
+		assert.True(t, c.path.IsEqual(actual), caseName)
+	}
+}
+
+func TestParseWithOptions_Whitespace(t *testing.T) {
+	cases := map[string]struct {
+		input    string
+		expected Pather
+	}{
+		"around dots": {
+			input:    " dogs . name ",
+			expected: New(NewInstanceVariableNamed("dogs"), NewInstanceVariableNamed("name")),
+		},
+		"around brackets": {
+			input:    "dogs [ 0 ] [ \"key\" ]\n.name",
+			expected: New(NewInstanceVariableNamed("dogs"), NewArrayIndex(0), NewMapKey("key"), NewInstanceVariableNamed("name")),
+		},
+		"range": {
+			input:    "dogs[ 1 : -2 : 2 ]",
+			expected: New(NewInstanceVariableNamed("dogs"), NewArrayRange(optional.NewIntFrom(1), optional.NewIntFrom(-2), optional.NewIntFrom(2))),
+		},
+		"index union": {
+			input:    "dogs[ 0 , 3 ]",
+			expected: New(NewInstanceVariableNamed("dogs"), MustNewUnion(NewArrayIndex(0), NewArrayIndex(3))),
+		},
+		"keyed element": {
+			input:    `servers[ name = "web 1" ].port`,
+			expected: New(NewInstanceVariableNamed("servers"), NewKeyedElement("name", "web 1"), NewInstanceVariableNamed("port")),
+		},
+		"typed map key": {
+			input:    "counts[ ( 42 ) ]",
+			expected: New(NewInstanceVariableNamed("counts"), NewMapKeyOf(42)),
+		},
+		"field union": {
+			input:    "user. { name , 'e mail' }",
+			expected: New(NewInstanceVariableNamed("user"), MustNewUnion(NewInstanceVariableNamed("name"), NewInstanceVariableNamed("e mail"))),
+		},
+		"wildcards and descendants": {
+			input:    "users [ * ] . * .. email",
+			expected: New(NewInstanceVariableNamed("users"), NewIndexWildcard(), NewWildcard(), NewDescendantNamed("email")),
+		},
+		"only whitespace": {
+			input:    " \t\n",
+			expected: NewRoot(),
+		},
+	}
+
+	for caseName, c := range cases {
+		actual, err := ParseWithOptions(strings.NewReader(c.input), ParseOptions{AllowWhitespace: true})
+		require.NoError(t, err, caseName)
+		assert.True(t, c.expected.IsEqual(actual), "%s: got %s", caseName, actual)
+
+		_, err = ParseString(c.input)
+		assert.Error(t, err, caseName)
+	}
+}
+
+func TestParseWithOptions_WhitespaceWithinTokens(t *testing.T) {
+	cases := map[string]struct {
+		input string
+	}{
+		"name":           {input: "pu ppy"},
+		"index":          {input: "dogs[1 2]"},
+		"negative index": {input: "dogs[- 1]"},
+		"descendant":     {input: "dogs. .name"},
+		"keyed field":    {input: "servers[na me=1]"},
+		"keyed value":    {input: "servers[name=1 2]"},
+		"union name":     {input: "{first name,email}"},
+	}
+
+	for caseName, c := range cases {
+		_, err := ParseWithOptions(strings.NewReader(c.input), ParseOptions{AllowWhitespace: true})
+		assert.Error(t, err, caseName)
+	}
+}
+
+func TestParseWithOptions_Limits(t *testing.T) {
+	cases := map[string]struct {
+		input   string
+		options ParseOptions
+		check   func(t *testing.T, err error, caseName string)
+	}{
+		"within limits": {
+			input:   "dogs[10].names[-10:10]",
+			options: ParseOptions{MaxLength: 22, MaxComponents: 4, MaxArrayIndex: 10},
+			check: func(t *testing.T, err error, caseName string) {
+				assert.NoError(t, err, caseName)
+			},
+		},
+		"too long": {
+			input:   "dogs." + strings.Repeat("a", 1<<20),
+			options: ParseOptions{MaxLength: 100},
+			check: func(t *testing.T, err error, caseName string) {
+				var limitErr *LengthLimitError
+				require.True(t, errors.As(err, &limitErr), caseName)
+				assert.Equal(t, 100, limitErr.Limit, caseName)
+				var parseErr *ParseError
+				require.True(t, errors.As(err, &parseErr), caseName)
+				assert.Equal(t, 100, parseErr.Offset, caseName)
+			},
+		},
+		"too many components": {
+			input:   "a.b.c.d",
+			options: ParseOptions{MaxComponents: 3},
+			check: func(t *testing.T, err error, caseName string) {
+				var limitErr *ComponentLimitError
+				require.True(t, errors.As(err, &limitErr), caseName)
+				assert.Equal(t, 3, limitErr.Limit, caseName)
+				var parseErr *ParseError
+				require.True(t, errors.As(err, &parseErr), caseName)
+				assert.Equal(t, 6, parseErr.Offset, caseName)
+			},
+		},
+		"union counts once": {
+			input:   "a[0,1,2,3]",
+			options: ParseOptions{MaxComponents: 2},
+			check: func(t *testing.T, err error, caseName string) {
+				assert.NoError(t, err, caseName)
+			},
+		},
+		"index too large": {
+			input:   "dogs[1000000]",
+			options: ParseOptions{MaxArrayIndex: 1000},
+			check: func(t *testing.T, err error, caseName string) {
+				var limitErr *ArrayIndexLimitError
+				require.True(t, errors.As(err, &limitErr), caseName)
+				assert.Equal(t, 1000000, limitErr.Index, caseName)
+				assert.Equal(t, 1000, limitErr.Limit, caseName)
+			},
+		},
+		"negative index too large": {
+			input:   "dogs[-1001]",
+			options: ParseOptions{MaxArrayIndex: 1000},
+			check: func(t *testing.T, err error, caseName string) {
+				var limitErr *ArrayIndexLimitError
+				assert.True(t, errors.As(err, &limitErr), caseName)
+			},
+		},
+		"range bound too large": {
+			input:   "dogs[0:5000]",
+			options: ParseOptions{MaxArrayIndex: 1000},
+			check: func(t *testing.T, err error, caseName string) {
+				var limitErr *ArrayIndexLimitError
+				assert.True(t, errors.As(err, &limitErr), caseName)
+			},
+		},
+		"smallest index": {
+			input:   "dogs[-9223372036854775808]",
+			options: ParseOptions{MaxArrayIndex: 10},
+			check: func(t *testing.T, err error, caseName string) {
+				var limitErr *ArrayIndexLimitError
+				assert.True(t, errors.As(err, &limitErr), caseName)
+			},
+		},
+		"filter index too large": {
+			input:   "dogs[?(@[100])]",
+			options: ParseOptions{MaxArrayIndex: 10},
+			check: func(t *testing.T, err error, caseName string) {
+				var limitErr *ArrayIndexLimitError
+				assert.True(t, errors.As(err, &limitErr), caseName)
+			},
+		},
+		"filter path too long": {
+			input:   "dogs[?(@.b.c.d.e)]",
+			options: ParseOptions{MaxComponents: 3},
+			check: func(t *testing.T, err error, caseName string) {
+				var limitErr *ComponentLimitError
+				assert.True(t, errors.As(err, &limitErr), caseName)
+			},
+		},
+		"union index too large": {
+			input:   "dogs[0,5000]",
+			options: ParseOptions{MaxArrayIndex: 1000},
+			check: func(t *testing.T, err error, caseName string) {
+				var limitErr *ArrayIndexLimitError
+				assert.True(t, errors.As(err, &limitErr), caseName)
+			},
+		},
+	}
+
+	for caseName, c := range cases {
+		_, err := ParseWithOptions(strings.NewReader(c.input), c.options)
+		c.check(t, err, caseName)
+	}
+}
+
+func TestParseWithOptions_NotExportedError(t *testing.T) {
+	_, err := ParseWithOptions(strings.NewReader("Dogs.name"), ParseOptions{Identifiers: IdentifierModeGoExported})
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, "name is not exported", parseErr.Message)
+}
@@ -0,0 +1,76 @@
+package go_path
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestParseString(t *testing.T) {
+	expected := New(NewInstanceVariableNamed("dogs"), NewArrayIndex(5), NewMapKey("fur"))
+	actual, err := ParseString(`dogs[5]["fur"]`)
+	require.NoError(t, err)
+	assert.True(t, expected.IsEqual(actual))
+
+	actual, err = ParseBytes([]byte(`dogs[5]["fur"]`))
+	require.NoError(t, err)
+	assert.True(t, expected.IsEqual(actual))
+
+	_, err = ParseString("dogs[")
+	assert.Error(t, err)
+}
+
+func TestParseString_MatchesParse(t *testing.T) {
+	cases := map[string]struct {
+		input string
+	}{
+		"typical":          {input: `dogs[5].attributes["fur"].color`},
+		"escaped map key":  {input: `labels["a\"b"]`},
+		"typed map key":    {input: `counts[(42)]["x"]`},
+		"quoted name":      {input: `dogs.'it\'s'.name`},
+		"escape first":     {input: `'\'quoted'`},
+		"keyed and filter": {input: `servers[name="web"].ports[?(@ > 80)]`},
+		"union":            {input: `user.{name,'e-mail'}[0,2]`},
+		"multi-byte":       {input: `größe["ü"]`},
+	}
+
+	for caseName, c := range cases {
+		expected, err := Parse(strings.NewReader(c.input))
+		require.NoError(t, err, caseName)
+		actual, err := ParseString(c.input)
+		require.NoError(t, err, caseName)
+		assert.True(t, expected.IsEqual(actual), "%s: got %s", caseName, actual)
+	}
+}
+
+func TestParseString_Allocations(t *testing.T) {
+	// the path, with room for its components, and one allocation per component
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = ParseString(`dogs[5].attributes["fur"].color`)
+	})
+	assert.LessOrEqual(t, allocs, 6.0)
+}
+
+func TestMustParse(t *testing.T) {
+	p := MustParse("dogs[0].name")
+	assert.Equal(t, "dogs[0].name", p.String())
+	assert.Panics(t, func() {
+		MustParse("dogs[")
+	})
+}
+
+func TestFreeze(t *testing.T) {
+	original := New(NewInstanceVariableNamed("dogs"), NewArrayIndex(0))
+	frozen := Freeze(original)
+	_, isMutable := frozen.(PathMutator)
+	assert.False(t, isMutable)
+	assert.True(t, frozen.IsEqual(original))
+	assert.True(t, original.IsEqual(frozen))
+	assert.True(t, frozen.IsEqual(Freeze(frozen)))
+
+	modified := frozen.Copy()
+	modified.Append(NewInstanceVariableNamed("name"))
+	assert.Equal(t, "dogs[0].name", modified.String())
+	assert.Equal(t, "dogs[0]", frozen.String())
+}
@@ -1,6 +1,7 @@
 package go_path
 
 import (
+	"errors"
 	"fmt"
 	"github.com/wojnosystems/go-path"
 	"io"
@@ -24,15 +25,25 @@ func New(components ...Componenter) PathMutator {
 	return n
 }
 
+// componentParter is implemented by the paths of this package, so they can be compared with each other
+type componentParter interface {
+	componentParts() []Componenter
+}
+
+func (p goPath) componentParts() []Componenter {
+	return p.parts
+}
+
 func (p goPath) IsEqual(compareWith path.Pather) bool {
-	if compareWithGo, ok := compareWith.(*goPath); !ok {
+	if compareWithGo, ok := compareWith.(componentParter); !ok {
 		return false
 	} else {
-		if len(p.parts) != len(compareWithGo.parts) {
+		compareParts := compareWithGo.componentParts()
+		if len(p.parts) != len(compareParts) {
 			return false
 		}
 		for partIndex, part := range p.parts {
-			if !part.(Componenter).IsEqual(compareWithGo.parts[partIndex].(Componenter)) {
+			if !part.(Componenter).IsEqual(compareParts[partIndex].(Componenter)) {
 				return false
 			}
 		}
@@ -47,7 +58,7 @@ func (p goPath) serialize() string {
 	sb := strings.Builder{}
 	for i, component := range p.parts {
 		if i != 0 {
-			if _, ok := component.(*pathStructInstanceVariable); ok {
+			if isDotted(component) {
 				sb.WriteString(".")
 			}
 		}
@@ -56,38 +67,168 @@ func (p goPath) serialize() string {
 	return sb.String()
 }
 
+// isDotted is true for components that are separated from the preceding component by a dot
+func isDotted(component Componenter) bool {
+	switch c := component.(type) {
+	case WildcardComponenter:
+		return !c.Bracketed()
+	case UnionComponenter:
+		return c.Braced()
+	}
+	return component.Kind() == ComponentKindStruct
+}
+
 func (p goPath) String() string {
 	return p.serialize()
 }
 
+// Parse reads a path, accepting field names made of letters, digits and underscores, see ParseWithOptions
 func Parse(reader io.Reader) (out Pather, err error) {
-	lex := newLexer(reader)
-	go lex.lex()
-	outGo := NewRoot()
+	return ParseWithOptions(reader, ParseOptions{})
+}
+
+// ParseWithOptions reads a path, accepting the field names and whitespace allowed by the options, within their limits
+func ParseWithOptions(reader io.Reader, options ParseOptions) (out Pather, err error) {
+	lex := newLexer(reader, options)
+	defer lex.release()
+	return parse(lex, options)
+}
+
+// parse builds the path from the items emitted by lex
+func parse(lex *lexer, options ParseOptions) (out Pather, err error) {
+	// the path is allocated with room for the components of typical paths, so that parts rarely has to grow
+	holder := &struct {
+		path  goPath
+		parts [8]Componenter
+	}{}
+	parts := holder.parts[:0]
+	// alternatives of the union being parsed, nil when not parsing a union
+	var union []Componenter
+	// unionItem is the first alternative of the union, where errors about the union are reported
+	var unionItem, lastItem item
+	awaitingAlternative := false
 	continueParsing := true
 	for continueParsing {
 		item := lex.getNextItem()
 		switch item.typ {
 		case itemError:
 			continueParsing = false
-			err = fmt.Errorf("error parsing: \"%s\" #line %d:%d", item.val, item.line, item.col)
-		case itemVariableName:
-			outGo.Append(NewInstanceVariableNamed(item.val))
-		case itemMapKey:
-			outGo.Append(NewMapKey(item.val))
-		case itemArrayIndex:
-			var val int64
-			val, err = strconv.ParseInt(item.val, 10, 64)
-			if err != nil {
-				return
+			err = lex.err
+		case itemComma:
+			// the lexer only emits commas after a component within brackets
+			if union == nil {
+				union = []Componenter{parts[len(parts)-1]}
+				parts = parts[:len(parts)-1]
+				unionItem = lastItem
 			}
-			outGo.Append(NewArrayIndex(int(val)))
+			awaitingAlternative = true
 		case itemEOF:
 			continueParsing = false
+			fallthrough
+		default:
+			var component Componenter
+			if item.typ != itemEOF {
+				component, err = componentFromItem(item, options)
+				if err == nil {
+					err = options.validateComponent(component)
+				}
+				if err != nil {
+					err = newItemParseError(item, err)
+					continueParsing = false
+					break
+				}
+				lastItem = item
+			}
+			if awaitingAlternative {
+				union = append(union, component)
+				awaitingAlternative = false
+				break
+			}
+			if union != nil {
+				var unionComponent Componenter
+				unionComponent, err = NewUnion(union...)
+				if err != nil {
+					err = newItemParseError(unionItem, err)
+					continueParsing = false
+					break
+				}
+				parts = append(parts, unionComponent)
+				union = nil
+			}
+			if component != nil {
+				if options.MaxComponents > 0 && len(parts) >= options.MaxComponents {
+					err = newItemParseError(item, &ComponentLimitError{Limit: options.MaxComponents})
+					continueParsing = false
+					break
+				}
+				parts = append(parts, component)
+			}
+		}
+	}
+	holder.path.parts = parts
+	return &holder.path, err
+}
+
+// newItemParseError describes an item that could not be converted into a component
+func newItemParseError(item item, err error) *ParseError {
+	e := &ParseError{
+		Offset:  item.offset,
+		Line:    int(item.line),
+		Col:     int(item.col),
+		Message: err.Error(),
+		Err:     err,
+	}
+	var syntaxErr *filterSyntaxError
+	if errors.As(err, &syntaxErr) {
+		// filter expressions are the whole value of their item
+		e.Offset += len(string([]rune(item.val)[:syntaxErr.position]))
+		e.Col += syntaxErr.position
+		e.Message = "invalid filter expression: " + syntaxErr.message
+	}
+	return e
+}
+
+// componentFromItem creates the component for an item emitted by the lexer. The relative paths of filters are parsed
+// with options
+func componentFromItem(item item, options ParseOptions) (Componenter, error) {
+	switch item.typ {
+	case itemVariableName:
+		return NewInstanceVariableNamed(item.val), nil
+	case itemMapKey:
+		if !strings.ContainsAny(item.val, "\\\n") {
+			// without escapes, the key is exactly what was between the quotes
+			return NewMapKey(item.val), nil
+		}
+		key, err := strconv.Unquote("\"" + item.val + "\"")
+		if err != nil {
+			return nil, fmt.Errorf("invalid map key \"%s\"", item.val)
+		}
+		return NewMapKey(key), nil
+	case itemTypedMapKey:
+		return newParsedTypedMapKey(item.val)
+	case itemArrayIndex:
+		val, err := strconv.ParseInt(item.val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid array index \"%s\"", item.val)
 		}
+		return NewArrayIndex(int(val)), nil
+	case itemArrayRange:
+		return parseArrayRange(item.val)
+	case itemKeyed:
+		return newParsedKeyedElement(splitKeyedElement(item.val))
+	case itemFilter:
+		return newFilter(item.val, options)
+	case itemFieldUnion:
+		return newParsedFieldUnion(item.val)
+	case itemDescendant:
+		return NewDescendantNamed(item.val), nil
+	case itemWildcard:
+		return NewWildcard(), nil
+	case itemIndexWildcard:
+		return NewIndexWildcard(), nil
+	default:
+		return nil, fmt.Errorf("unexpected %s", item.String())
 	}
-	lex.drain()
-	return outGo, err
 }
 
 func (p goPath) Copy() PathMutator {
@@ -9,6 +9,8 @@ type pathArrayInstanceVariable struct {
 	index int
 }
 
+// NewArrayIndex creates a component identifying the element at index of a slice or array.
+// Negative indexes count back from the end: -1 is the last element.
 func NewArrayIndex(index int) Componenter {
 	return &pathArrayInstanceVariable{
 		index: index,
@@ -29,3 +31,25 @@ func (p pathArrayInstanceVariable) IsEqual(componenter paths.Componenter) bool {
 func (p pathArrayInstanceVariable) String() string {
 	return fmt.Sprintf("[%d]", p.index)
 }
+
+func (p pathArrayInstanceVariable) Kind() ComponentKind {
+	return ComponentKindArray
+}
+
+func (p *pathArrayInstanceVariable) Accept(visitor ComponentVisitor) error {
+	return visitor.VisitIndex(p)
+}
+
+func (p pathArrayInstanceVariable) Index() int {
+	return p.index
+}
+
+// absoluteIndex converts the index into an index from the start of a slice or array with length elements
+// ok is false if the index is out of range
+func (p pathArrayInstanceVariable) absoluteIndex(length int) (index int, ok bool) {
+	index = p.index
+	if index < 0 {
+		index += length
+	}
+	return index, index >= 0 && index < length
+}
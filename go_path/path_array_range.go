@@ -0,0 +1,201 @@
+package go_path
+
+import (
+	"fmt"
+	"github.com/wojnosystems/go-optional"
+	paths "github.com/wojnosystems/go-path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type pathArrayRange struct {
+	start optional.Int
+	end   optional.Int
+	step  optional.Int
+}
+
+// RangeComponenter is a component that selects several elements of a slice or array, like a Python slice
+type RangeComponenter interface {
+	Componenter
+	// Start index, inclusive. Negative values count back from the end
+	Start() optional.Int
+	// End index, exclusive. Negative values count back from the end
+	End() optional.Int
+	// Step between selected indexes, 1 if unset. Negative steps select elements in reverse
+	Step() optional.Int
+	// Indexes selected in a slice or array of length elements, in the order they are selected
+	Indexes(length int) []int
+}
+
+// RangeVisitor may be implemented by a ComponentVisitor to visit RangeComponenters
+type RangeVisitor interface {
+	VisitRange(component RangeComponenter) error
+}
+
+// NewArrayRange creates a component selecting the elements from start up to, but not including, end, every step
+// elements. It is serialized as [start:end:step], and each part may be left unset, as in [1:3] or [::2].
+// Unset parts default as they do in Python: the whole slice with a step of 1. A step of 0 selects nothing.
+func NewArrayRange(start, end, step optional.Int) Componenter {
+	return &pathArrayRange{
+		start: start,
+		end:   end,
+		step:  step,
+	}
+}
+
+// parseArrayRange creates a range from its literal form, as found between the brackets: start:end or start:end:step.
+// Like NewArrayRange, it accepts a step of 0, which selects nothing
+func parseArrayRange(literal string) (Componenter, error) {
+	parts := strings.Split(literal, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid array range %s", literal)
+	}
+	bounds := make([]optional.Int, 3)
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid array range %s", literal)
+		}
+		bounds[i].Set(int(value))
+	}
+	return NewArrayRange(bounds[0], bounds[1], bounds[2]), nil
+}
+
+func (p pathArrayRange) IsEqual(componenter paths.Componenter) bool {
+	if componenter == nil {
+		return false
+	}
+	if component, ok := componenter.(*pathArrayRange); !ok {
+		return false
+	} else {
+		return p.start.IsEqual(&component.start) && p.end.IsEqual(&component.end) && p.step.IsEqual(&component.step)
+	}
+}
+
+func (p pathArrayRange) String() string {
+	s := "[" + optionalIntString(p.start) + ":" + optionalIntString(p.end)
+	if p.step.IsSet() {
+		s += ":" + optionalIntString(p.step)
+	}
+	return s + "]"
+}
+
+func optionalIntString(i optional.Int) string {
+	if !i.IsSet() {
+		return ""
+	}
+	return strconv.Itoa(i.Value())
+}
+
+func (p pathArrayRange) Kind() ComponentKind {
+	return ComponentKindArrayRange
+}
+
+func (p *pathArrayRange) Accept(visitor ComponentVisitor) error {
+	if rangeVisitor, ok := visitor.(RangeVisitor); ok {
+		return rangeVisitor.VisitRange(p)
+	}
+	return newVisitorUnsupportedError(p)
+}
+
+func (p pathArrayRange) Start() optional.Int {
+	return p.start
+}
+
+func (p pathArrayRange) End() optional.Int {
+	return p.end
+}
+
+func (p pathArrayRange) Step() optional.Int {
+	return p.step
+}
+
+func (p pathArrayRange) Indexes(length int) []int {
+	step := 1
+	if p.step.IsSet() {
+		step = p.step.Value()
+	}
+	if step == 0 {
+		return []int{}
+	}
+	// bounds that indexes are clamped to, which differ in reverse so that the first element can be included
+	lower, upper := 0, length
+	if step < 0 {
+		lower, upper = -1, length-1
+	}
+	start := rangeBound(p.start, length, lower, upper, step > 0)
+	end := rangeBound(p.end, length, lower, upper, step < 0)
+
+	indexes := make([]int, 0)
+	for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// rangeBound resolves a start or end index against a slice of length elements, clamping it to [lower, upper]
+func rangeBound(bound optional.Int, length, lower, upper int, defaultLower bool) int {
+	if !bound.IsSet() {
+		if defaultLower {
+			return lower
+		}
+		return upper
+	}
+	i := bound.Value()
+	if i < 0 {
+		i += length
+	}
+	if i < lower {
+		return lower
+	}
+	if i > upper {
+		return upper
+	}
+	return i
+}
+
+func (p pathArrayRange) matchesComponent(component Componenter) bool {
+	indexComponent, ok := component.(IndexComponenter)
+	if !ok {
+		return false
+	}
+	index := indexComponent.Index()
+	step := 1
+	if p.step.IsSet() {
+		step = p.step.Value()
+	}
+	if index < 0 || step == 0 {
+		return false
+	}
+	if step < 0 || (p.start.IsSet() && p.start.Value() < 0) || (p.end.IsSet() && p.end.Value() < 0) {
+		// which indexes are selected depends on the length
+		return true
+	}
+	start := 0
+	if p.start.IsSet() {
+		start = p.start.Value()
+	}
+	if index < start || (p.end.IsSet() && index >= p.end.Value()) {
+		return false
+	}
+	return (index-start)%step == 0
+}
+
+func (p *pathArrayRange) expand(v reflect.Value) ([]expansion, *ResolveError) {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, newResolveError(ResolveReasonKindMismatch, v)
+	}
+	indexes := p.Indexes(v.Len())
+	expansions := make([]expansion, len(indexes))
+	for i, index := range indexes {
+		expansions[i] = expansion{
+			path:  []Componenter{NewArrayIndex(index)},
+			value: v.Index(index),
+		}
+	}
+	return expansions, nil
+}
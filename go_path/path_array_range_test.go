@@ -0,0 +1,75 @@
+package go_path
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wojnosystems/go-optional"
+	"testing"
+)
+
+func TestPathArrayRange_Indexes(t *testing.T) {
+	unset := optional.NewInt()
+	cases := map[string]struct {
+		start, end, step optional.Int
+		length           int
+		expected         []int
+	}{
+		"everything": {
+			start: unset, end: unset, step: unset,
+			length:   3,
+			expected: []int{0, 1, 2},
+		},
+		"start and end": {
+			start: optional.NewIntFrom(1), end: optional.NewIntFrom(3), step: unset,
+			length:   5,
+			expected: []int{1, 2},
+		},
+		"step": {
+			start: unset, end: unset, step: optional.NewIntFrom(2),
+			length:   5,
+			expected: []int{0, 2, 4},
+		},
+		"negative bounds": {
+			start: optional.NewIntFrom(-2), end: unset, step: unset,
+			length:   5,
+			expected: []int{3, 4},
+		},
+		"clamped": {
+			start: optional.NewIntFrom(-10), end: optional.NewIntFrom(10), step: unset,
+			length:   2,
+			expected: []int{0, 1},
+		},
+		"reverse": {
+			start: unset, end: unset, step: optional.NewIntFrom(-1),
+			length:   3,
+			expected: []int{2, 1, 0},
+		},
+		"reverse bounded": {
+			start: optional.NewIntFrom(3), end: optional.NewIntFrom(0), step: optional.NewIntFrom(-2),
+			length:   5,
+			expected: []int{3, 1},
+		},
+		"empty": {
+			start: optional.NewIntFrom(3), end: optional.NewIntFrom(1), step: unset,
+			length:   5,
+			expected: []int{},
+		},
+		"zero step": {
+			start: unset, end: unset, step: optional.NewIntFrom(0),
+			length:   5,
+			expected: []int{},
+		},
+	}
+
+	for caseName, c := range cases {
+		r := NewArrayRange(c.start, c.end, c.step).(RangeComponenter)
+		assert.Equal(t, c.expected, r.Indexes(c.length), caseName)
+	}
+}
+
+func TestPathArrayRange_String(t *testing.T) {
+	assert.Equal(t, "[1:3]", NewArrayRange(optional.NewIntFrom(1), optional.NewIntFrom(3), optional.NewInt()).String())
+	assert.Equal(t, "[::2]", NewArrayRange(optional.NewInt(), optional.NewInt(), optional.NewIntFrom(2)).String())
+	assert.Equal(t, "dogs[:-1].name", New(NewInstanceVariableNamed("dogs"),
+		NewArrayRange(optional.NewInt(), optional.NewIntFrom(-1), optional.NewInt()),
+		NewInstanceVariableNamed("name")).String())
+}
@@ -0,0 +1,146 @@
+package go_path
+
+import (
+	paths "github.com/wojnosystems/go-path"
+	"reflect"
+)
+
+// pathDescendant selects the field or string map key with a name at any depth beneath a value, including the value
+// itself, like JSONPath's descendant operator. It is serialized as ..name
+type pathDescendant struct {
+	name string
+}
+
+// DescendantComponenter is a component that selects fields and string map keys with a name at any depth
+type DescendantComponenter interface {
+	Componenter
+	// Name of the fields and map keys selected
+	Name() string
+}
+
+// DescendantVisitor may be implemented by a ComponentVisitor to visit DescendantComponenters
+type DescendantVisitor interface {
+	VisitDescendant(component DescendantComponenter) error
+}
+
+// NewDescendantNamed creates a component selecting every exported struct field and string map key called name at any
+// depth beneath a value: ..password
+func NewDescendantNamed(name string) Componenter {
+	return &pathDescendant{name: name}
+}
+
+func (p pathDescendant) IsEqual(componenter paths.Componenter) bool {
+	if componenter == nil {
+		return false
+	}
+	if component, ok := componenter.(*pathDescendant); !ok {
+		return false
+	} else {
+		return p.name == component.name
+	}
+}
+
+func (p pathDescendant) String() string {
+	return ".." + quoteFieldName(p.name)
+}
+
+func (p pathDescendant) Kind() ComponentKind {
+	return ComponentKindDescendant
+}
+
+func (p *pathDescendant) Accept(visitor ComponentVisitor) error {
+	if descendantVisitor, ok := visitor.(DescendantVisitor); ok {
+		return descendantVisitor.VisitDescendant(p)
+	}
+	return newVisitorUnsupportedError(p)
+}
+
+func (p pathDescendant) Name() string {
+	return p.name
+}
+
+// selects is true if the concrete component locates a child called name
+func (p pathDescendant) selects(component Componenter) bool {
+	switch c := component.(type) {
+	case FieldComponenter:
+		return c.FieldName() == p.name
+	case *pathMapInstanceVariable:
+		return c.Key() == p.name
+	default:
+		return false
+	}
+}
+
+func (p *pathDescendant) expand(v reflect.Value) ([]expansion, *ResolveError) {
+	s := descendantSearch{
+		name:      p.name,
+		ancestors: make(map[pointerKey]bool),
+		found:     make([]expansion, 0),
+	}
+	if v.CanAddr() {
+		// v was usually found through a pointer, which may be pointed to again beneath it
+		s.ancestors[newPointerKey(v.Addr())] = true
+	}
+	s.search(v, make([]Componenter, 0))
+	return s.found, nil
+}
+
+type descendantSearch struct {
+	name string
+	// ancestors are the pointers, maps and slices being searched, so that cycles are not searched forever
+	ancestors map[pointerKey]bool
+	found     []expansion
+}
+
+// search records the children of v called name and then searches every child of v. v is located at path
+func (s *descendantSearch) search(v reflect.Value, path []Componenter) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		if v.Kind() == reflect.Ptr {
+			key := newPointerKey(v)
+			if s.ancestors[key] {
+				return
+			}
+			s.ancestors[key] = true
+			defer delete(s.ancestors, key)
+		}
+		v = v.Elem()
+	}
+	if (v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && isReference(v) {
+		key := newPointerKey(v)
+		if s.ancestors[key] {
+			return
+		}
+		s.ancestors[key] = true
+		defer delete(s.ancestors, key)
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if field, ok := v.Type().FieldByName(s.name); ok && field.PkgPath == "" && len(field.Index) == 1 {
+			s.record(path, NewInstanceVariableNamed(s.name), v.FieldByIndex(field.Index))
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			key := reflect.ValueOf(s.name).Convert(v.Type().Key())
+			if value := v.MapIndex(key); value.IsValid() {
+				s.record(path, NewMapKey(s.name), value)
+			}
+		}
+	}
+	children, err := (&pathWildcard{}).expand(v)
+	if err != nil {
+		return
+	}
+	for _, child := range children {
+		s.search(child.value, append(path, child.path...))
+	}
+}
+
+func (s *descendantSearch) record(path []Componenter, component Componenter, value reflect.Value) {
+	found := make([]Componenter, len(path)+1)
+	copy(found, path)
+	found[len(path)] = component
+	s.found = append(s.found, expansion{path: found, value: value})
+}
@@ -0,0 +1,107 @@
+package go_path
+
+import (
+	paths "github.com/wojnosystems/go-path"
+	"reflect"
+)
+
+// pathFilter selects the elements of a slice or array, or the values of a map, for which its expression is true.
+// It is serialized as [?(expression)], see filter_expression.go for the expression grammar
+type pathFilter struct {
+	expression string
+	predicate  filterExpression
+}
+
+// FilterComponenter is a component that selects the elements of a slice, array or map that satisfy an expression
+type FilterComponenter interface {
+	Componenter
+	// Expression is the source of the filter expression, as written between [?( and )]
+	Expression() string
+	// Test is true if element satisfies the filter expression
+	Test(element reflect.Value) bool
+}
+
+// FilterVisitor may be implemented by a ComponentVisitor to visit FilterComponenters
+type FilterVisitor interface {
+	VisitFilter(component FilterComponenter) error
+}
+
+// NewFilter creates a component selecting the elements that satisfy expression, such as `@.age > 21 && @.active`.
+// @ is the element, and may be followed by a path relative to the element. Expressions may compare values with ==,
+// !=, <, <=, > and >=, test that a path exists and combine tests with &&, || and !
+func NewFilter(expression string) (Componenter, error) {
+	return newFilter(expression, ParseOptions{})
+}
+
+// newFilter creates a filter whose relative paths are parsed with options
+func newFilter(expression string, options ParseOptions) (Componenter, error) {
+	predicate, err := parseFilterExpression(expression, options)
+	if err != nil {
+		return nil, err
+	}
+	return &pathFilter{
+		expression: expression,
+		predicate:  predicate,
+	}, nil
+}
+
+func (p pathFilter) IsEqual(componenter paths.Componenter) bool {
+	if componenter == nil {
+		return false
+	}
+	if component, ok := componenter.(*pathFilter); !ok {
+		return false
+	} else {
+		return p.expression == component.expression
+	}
+}
+
+func (p pathFilter) String() string {
+	return "[?(" + p.expression + ")]"
+}
+
+func (p pathFilter) Kind() ComponentKind {
+	return ComponentKindFilter
+}
+
+func (p *pathFilter) Accept(visitor ComponentVisitor) error {
+	if filterVisitor, ok := visitor.(FilterVisitor); ok {
+		return filterVisitor.VisitFilter(p)
+	}
+	return newVisitorUnsupportedError(p)
+}
+
+func (p pathFilter) Expression() string {
+	return p.expression
+}
+
+func (p pathFilter) Test(element reflect.Value) bool {
+	return p.predicate.evaluate(element)
+}
+
+// matchesComponent is true for every index and map key, as whether they are selected depends on the value
+func (p pathFilter) matchesComponent(component Componenter) bool {
+	switch component.(type) {
+	case IndexComponenter, MapKeyComponenter, KeyedElementComponenter:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *pathFilter) expand(v reflect.Value) ([]expansion, *ResolveError) {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array && v.Kind() != reflect.Map {
+		return nil, newResolveError(ResolveReasonKindMismatch, v)
+	}
+	children, err := (&pathWildcard{}).expand(v)
+	if err != nil {
+		return nil, err
+	}
+	expansions := make([]expansion, 0, len(children))
+	for _, child := range children {
+		if p.Test(child.value) {
+			expansions = append(expansions, child)
+		}
+	}
+	return expansions, nil
+}
@@ -0,0 +1,42 @@
+package go_path
+
+import (
+	"github.com/wojnosystems/go-path"
+)
+
+// frozenPath is a path that cannot be modified, so it can be shared, such as by ParseCache.
+// It does not implement PathMutator; Copy returns a path that can be modified
+type frozenPath struct {
+	path goPath
+}
+
+// Freeze returns a path with the components of p that cannot be modified, not even by type assertion.
+// Frozen paths are equal to other paths with the same components
+func Freeze(p Pather) Pather {
+	if frozen, ok := p.(*frozenPath); ok {
+		return frozen
+	}
+	return &frozenPath{
+		path: goPath{parts: components(p)},
+	}
+}
+
+func (p frozenPath) IsEqual(compareWith path.Pather) bool {
+	return p.path.IsEqual(compareWith)
+}
+
+func (p frozenPath) String() string {
+	return p.path.String()
+}
+
+func (p frozenPath) Copy() PathMutator {
+	return p.path.Copy()
+}
+
+func (p frozenPath) Each(yield func(index int, componenter Componenter)) {
+	p.path.Each(yield)
+}
+
+func (p frozenPath) componentParts() []Componenter {
+	return p.path.parts
+}
@@ -0,0 +1,166 @@
+package go_path
+
+import (
+	paths "github.com/wojnosystems/go-path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pathKeyedElement identifies the element of a slice or array of structs by the value of one of its fields, like the
+// merge keys of Kubernetes strategic merge patches. Paths using it keep locating the same element when the slice is
+// reordered. It is serialized as [field=value], where value is written like a typed map key: [name="web"], [port=80]
+type pathKeyedElement struct {
+	field string
+	value *pathTypedMapKey
+}
+
+// KeyedElementComponenter is a component that identifies the element of a slice or array by the value of a field
+type KeyedElementComponenter interface {
+	Componenter
+	// KeyField is the name of the field compared. It is matched like JSONPointerToPath matches field names
+	KeyField() string
+	// KeyLiteral is the literal form of the value the field must have: a quoted string, a number or a boolean
+	KeyLiteral() string
+	// KeyValue is the Go value the field must have, nil if the component was parsed
+	KeyValue() interface{}
+}
+
+// KeyedElementVisitor may be implemented by a ComponentVisitor to visit KeyedElementComponenters
+type KeyedElementVisitor interface {
+	VisitKeyedElement(component KeyedElementComponenter) error
+}
+
+// NewKeyedElement creates a component identifying the only element of a slice or array whose field has value.
+// Values are serialized as typed map keys are, see NewMapKeyOf
+func NewKeyedElement(field string, value interface{}) Componenter {
+	key, ok := NewMapKeyOf(value).(*pathTypedMapKey)
+	if !ok {
+		// strings are not typed map keys, but are written the same way
+		key = &pathTypedMapKey{literal: strconv.Quote(reflect.ValueOf(value).String()), key: value}
+	}
+	return &pathKeyedElement{
+		field: field,
+		value: key,
+	}
+}
+
+// splitKeyedElement splits a keyed element, as found between the brackets, into its field name and value literal at the
+// first = that is not quoted. A quoted field name is returned without its quotes and escapes
+func splitKeyedElement(keyed string) (field, literal string) {
+	if !strings.HasPrefix(keyed, "'") {
+		separator := strings.Index(keyed, "=")
+		return keyed[:separator], keyed[separator+1:]
+	}
+	isEscaped := false
+	for i, r := range keyed[1:] {
+		if !isEscaped && isQuoteSingle(r) {
+			// the closing quote is followed by the =
+			return unquoteFieldName(keyed[1 : i+1]), keyed[i+3:]
+		}
+		isEscaped = !isEscaped && isEscapeChar(r)
+	}
+	return "", ""
+}
+
+// newParsedKeyedElement creates a keyed element from the field name and value literal found between the brackets
+func newParsedKeyedElement(field, literal string) (Componenter, error) {
+	value, err := newParsedTypedMapKey(literal)
+	if err != nil {
+		return nil, err
+	}
+	return &pathKeyedElement{
+		field: field,
+		value: value.(*pathTypedMapKey),
+	}, nil
+}
+
+func (p pathKeyedElement) IsEqual(componenter paths.Componenter) bool {
+	if componenter == nil {
+		return false
+	}
+	if component, ok := componenter.(*pathKeyedElement); !ok {
+		return false
+	} else {
+		return p.field == component.field && p.value.IsEqual(component.value)
+	}
+}
+
+func (p pathKeyedElement) String() string {
+	field := p.field
+	if !isGoIdentifier(field) {
+		// unquoted field names must start like an identifier, or they would be read as array indexes
+		field = quoteName(field)
+	}
+	return "[" + field + "=" + p.value.literal + "]"
+}
+
+func (p pathKeyedElement) Kind() ComponentKind {
+	return ComponentKindKeyedElement
+}
+
+func (p *pathKeyedElement) Accept(visitor ComponentVisitor) error {
+	if keyedVisitor, ok := visitor.(KeyedElementVisitor); ok {
+		return keyedVisitor.VisitKeyedElement(p)
+	}
+	return newVisitorUnsupportedError(p)
+}
+
+func (p pathKeyedElement) KeyField() string {
+	return p.field
+}
+
+func (p pathKeyedElement) KeyLiteral() string {
+	return p.value.literal
+}
+
+func (p pathKeyedElement) KeyValue() interface{} {
+	return p.value.key
+}
+
+// indexIn finds the index of the only element of v that has the key. v must already be indirected
+// The returned error has not been located within the path yet
+func (p pathKeyedElement) indexIn(v reflect.Value) (int, *ResolveError) {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return 0, newResolveError(ResolveReasonKindMismatch, v)
+	}
+	found := -1
+	for i := 0; i < v.Len(); i++ {
+		if !p.selects(v.Index(i)) {
+			continue
+		}
+		if found != -1 {
+			return 0, newResolveError(ResolveReasonAmbiguous, v)
+		}
+		found = i
+	}
+	if found == -1 {
+		return 0, newResolveError(ResolveReasonNoMatch, v)
+	}
+	return found, nil
+}
+
+// selects is true if element is a struct, or points at one, whose key field has the value
+func (p pathKeyedElement) selects(element reflect.Value) bool {
+	element, err := indirect(element)
+	if err != nil || element.Kind() != reflect.Struct {
+		return false
+	}
+	field, ok := jsonField(element.Type(), p.field)
+	if !ok {
+		return false
+	}
+	fieldValue, ok := field.valueIn(element)
+	if !ok {
+		return false
+	}
+	fieldValue, err = indirect(fieldValue)
+	if err != nil {
+		return false
+	}
+	key, ok := p.value.keyFor(fieldValue.Type())
+	if !ok {
+		return false
+	}
+	return valuesEqual(fieldValue, key)
+}
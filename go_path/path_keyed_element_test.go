@@ -0,0 +1,127 @@
+package go_path
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newTestKeyedConfig() *testConfig {
+	return &testConfig{
+		Servers: []testServer{
+			{Host: "db", Port: 5432},
+			{Host: "web", Port: 80, TLS: &testTLS{Cert: "pem"}},
+			{Host: "web-canary", Port: 80},
+		},
+	}
+}
+
+func TestKeyedElement_Get(t *testing.T) {
+	cases := map[string]struct {
+		path           string
+		expected       interface{}
+		expectedReason ResolveReason
+	}{
+		"string key": {
+			path:     `Servers[host="web"].Port`,
+			expected: 80,
+		},
+		"go field name": {
+			path:     `Servers[Host="db"].Port`,
+			expected: 5432,
+		},
+		"number key": {
+			path:     `Servers[port=5432].Host`,
+			expected: "db",
+		},
+		"no match": {
+			path:           `Servers[host="cache"]`,
+			expectedReason: ResolveReasonNoMatch,
+		},
+		"ambiguous": {
+			path:           `Servers[port=80]`,
+			expectedReason: ResolveReasonAmbiguous,
+		},
+		"not a slice": {
+			path:           `Labels[host="web"]`,
+			expectedReason: ResolveReasonKindMismatch,
+		},
+	}
+
+	for caseName, c := range cases {
+		p, err := Parse(bytes.NewBufferString(c.path))
+		require.NoError(t, err, caseName)
+		actual, err := GetInterface(newTestKeyedConfig(), p)
+		if c.expectedReason != ResolveReasonInvalid {
+			var resolveErr *ResolveError
+			require.True(t, errors.As(err, &resolveErr), caseName)
+			assert.Equal(t, c.expectedReason, resolveErr.Reason, caseName)
+			continue
+		}
+		require.NoError(t, err, caseName)
+		assert.Equal(t, c.expected, actual, caseName)
+	}
+}
+
+func TestKeyedElement_StableAcrossReorder(t *testing.T) {
+	config := newTestKeyedConfig()
+	p := New(NewInstanceVariableNamed("Servers"), NewKeyedElement("host", "web"), NewInstanceVariableNamed("Port"))
+	config.Servers[0], config.Servers[1] = config.Servers[1], config.Servers[0]
+
+	require.NoError(t, Set(config, p, 8080))
+	assert.Equal(t, 8080, config.Servers[0].Port)
+
+	require.NoError(t, Delete(config, New(NewInstanceVariableNamed("Servers"), NewKeyedElement("host", "web"))))
+	assert.Equal(t, []testServer{{Host: "db", Port: 5432}, {Host: "web-canary", Port: 80}}, config.Servers)
+}
+
+func TestKeyedElement_BoundNumbers(t *testing.T) {
+	config := newTestKeyedConfig()
+	host, err := GetInterface(config, New(NewInstanceVariableNamed("Servers"), NewKeyedElement("Port", 5432.0), NewInstanceVariableNamed("Host")))
+	require.NoError(t, err)
+	assert.Equal(t, "db", host)
+
+	_, err = Get(config, New(NewInstanceVariableNamed("Servers"), NewKeyedElement("Port", 80.5)))
+	var resolveErr *ResolveError
+	require.True(t, errors.As(err, &resolveErr))
+	assert.Equal(t, ResolveReasonNoMatch, resolveErr.Reason)
+}
+
+func TestKeyedElement_Error(t *testing.T) {
+	_, err := Get(newTestKeyedConfig(), New(NewInstanceVariableNamed("Servers"), NewKeyedElement("port", 80)))
+	assert.EqualError(t, err, "Servers[port=80]: more than one element matches")
+}
+
+func TestKeyedElement_QuotedFieldRoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		component Componenter
+		expected  string
+	}{
+		"identifier": {
+			component: NewKeyedElement("host", "web"),
+			expected:  `[host="web"]`,
+		},
+		"hyphen": {
+			component: NewKeyedElement("my-field", "x"),
+			expected:  `['my-field'="x"]`,
+		},
+		"leading digit": {
+			component: NewKeyedElement("9a", 1),
+			expected:  `['9a'=1]`,
+		},
+		"equals and quote": {
+			component: NewKeyedElement(`a='b`, true),
+			expected:  `['a=\'b'=true]`,
+		},
+	}
+
+	for caseName, c := range cases {
+		p := New(NewInstanceVariableNamed("Servers"), c.component)
+		assert.Equal(t, "Servers"+c.expected, p.String(), caseName)
+		actual, err := ParseString(p.String())
+		require.NoError(t, err, caseName)
+		assert.True(t, p.IsEqual(actual), caseName)
+	}
+}
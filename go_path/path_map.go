@@ -1,6 +1,10 @@
 package go_path
 
-import paths "github.com/wojnosystems/go-path"
+import (
+	paths "github.com/wojnosystems/go-path"
+	"reflect"
+	"strconv"
+)
 
 type pathMapInstanceVariable struct {
 	variableName string
@@ -24,5 +28,29 @@ func (p pathMapInstanceVariable) IsEqual(componenter paths.Componenter) bool {
 }
 
 func (p pathMapInstanceVariable) String() string {
-	return "[\"" + p.variableName + "\"]"
+	return "[" + strconv.Quote(p.variableName) + "]"
+}
+
+func (p pathMapInstanceVariable) Kind() ComponentKind {
+	return ComponentKindMap
+}
+
+func (p *pathMapInstanceVariable) Accept(visitor ComponentVisitor) error {
+	return visitor.VisitMapKey(p)
+}
+
+func (p pathMapInstanceVariable) Key() string {
+	return p.variableName
+}
+
+// keyFor converts the key into a value usable to index a map with keys of keyType
+// ok is false if the key cannot be used with that type of map
+func (p pathMapInstanceVariable) keyFor(keyType reflect.Type) (key reflect.Value, ok bool) {
+	stringType := reflect.TypeOf(p.variableName)
+	isStringKey := keyType.Kind() == reflect.String
+	isInterfaceKey := keyType.Kind() == reflect.Interface && stringType.Implements(keyType)
+	if !isStringKey && !isInterfaceKey {
+		return reflect.Value{}, false
+	}
+	return reflect.ValueOf(p.variableName).Convert(keyType), true
 }
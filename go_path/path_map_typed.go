@@ -0,0 +1,215 @@
+package go_path
+
+import (
+	"encoding"
+	"fmt"
+	paths "github.com/wojnosystems/go-path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pathTypedMapKey identifies the entry of a map whose keys are not strings.
+// The key is serialized as a literal in parentheses: [(42)], [(-1.5)], [(true)] or, for keys that implement
+// encoding.TextMarshaler, the quoted text: [("c9a6e0e2-...")]. Parsed keys only have the literal. The literal is
+// converted into a key of the map's key type when the path is resolved against a value, or by Bind.
+type pathTypedMapKey struct {
+	literal string
+	// key is the Go value of the key, nil if the key was parsed and not bound to a type yet
+	key interface{}
+}
+
+// TypedMapKeyComponenter is a MapKeyComponenter for maps with keys that are not strings.
+// Key returns the literal form of the key
+type TypedMapKeyComponenter interface {
+	MapKeyComponenter
+	// KeyValue is the Go value of the key, nil if the component was parsed and has not been bound, see Bind
+	KeyValue() interface{}
+	// KeyFor converts the key literal into a key for maps with keys of keyType
+	KeyFor(keyType reflect.Type) (interface{}, error)
+}
+
+// NewMapKeyOf creates a component identifying the map entry with key.
+// Keys with an underlying string type create the same component as NewMapKey. Any other key is serialized as a typed
+// literal, see TypedMapKeyComponenter. Keys that are not numbers, booleans or encoding.TextMarshalers are serialized
+// with their fmt %v representation as quoted text, which can only be resolved if the key type can unmarshal that text.
+func NewMapKeyOf(key interface{}) Componenter {
+	if s, ok := key.(string); ok {
+		return NewMapKey(s)
+	}
+	v := reflect.ValueOf(key)
+	if marshaler, ok := key.(encoding.TextMarshaler); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return &pathTypedMapKey{literal: strconv.Quote(string(text)), key: key}
+		}
+	}
+	literal := ""
+	switch v.Kind() {
+	case reflect.String:
+		return NewMapKey(v.String())
+	case reflect.Bool:
+		literal = strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		literal = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		literal = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		literal = strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	default:
+		literal = strconv.Quote(fmt.Sprint(key))
+	}
+	return &pathTypedMapKey{literal: literal, key: key}
+}
+
+// newParsedTypedMapKey creates a typed map key from its literal form, as found between the parentheses
+func newParsedTypedMapKey(literal string) (Componenter, error) {
+	if strings.HasPrefix(literal, "\"") {
+		text, err := strconv.Unquote(literal)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted key %s", literal)
+		}
+		return &pathTypedMapKey{literal: strconv.Quote(text)}, nil
+	}
+	if _, err := strconv.ParseBool(literal); err == nil {
+		return &pathTypedMapKey{literal: literal}, nil
+	}
+	if _, err := strconv.ParseFloat(literal, 64); err == nil {
+		return &pathTypedMapKey{literal: literal}, nil
+	}
+	return nil, fmt.Errorf("invalid key literal %s", literal)
+}
+
+func (p pathTypedMapKey) IsEqual(componenter paths.Componenter) bool {
+	if componenter == nil {
+		return false
+	}
+	if component, ok := componenter.(*pathTypedMapKey); !ok {
+		return false
+	} else {
+		return p.literal == component.literal
+	}
+}
+
+func (p pathTypedMapKey) String() string {
+	return "[(" + p.literal + ")]"
+}
+
+func (p pathTypedMapKey) Kind() ComponentKind {
+	return ComponentKindMap
+}
+
+func (p *pathTypedMapKey) Accept(visitor ComponentVisitor) error {
+	return visitor.VisitMapKey(p)
+}
+
+func (p pathTypedMapKey) Key() string {
+	return p.literal
+}
+
+func (p pathTypedMapKey) KeyValue() interface{} {
+	return p.key
+}
+
+func (p pathTypedMapKey) KeyFor(keyType reflect.Type) (interface{}, error) {
+	key, ok := p.keyFor(keyType)
+	if !ok {
+		return nil, fmt.Errorf("map key %s cannot be used with keys of type %s", p.String(), keyType.String())
+	}
+	return key.Interface(), nil
+}
+
+// keyFor converts the key into a value usable to index a map with keys of keyType
+// ok is false if the key cannot be used with that type of map
+func (p pathTypedMapKey) keyFor(keyType reflect.Type) (key reflect.Value, ok bool) {
+	if p.key != nil {
+		bound := reflect.ValueOf(p.key)
+		if bound.Type().AssignableTo(keyType) {
+			return bound, true
+		}
+		// numbers are only converted when no information is lost, so that a bound key finds the same map entry as
+		// its literal would
+		if converted, err := assignableValue(bound, keyType); err == nil {
+			return converted, true
+		}
+	}
+	if strings.HasPrefix(p.literal, "\"") {
+		return p.textKeyFor(keyType)
+	}
+	key = reflect.New(keyType).Elem()
+	switch keyType.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(p.literal)
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		key.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(p.literal, 10, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		key.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(p.literal, 10, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		key.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(p.literal, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		key.SetFloat(f)
+	case reflect.Interface:
+		// without a concrete type, prefer the simplest Go type the literal parses as
+		if i, err := strconv.ParseInt(p.literal, 10, 0); err == nil {
+			return p.interfaceKey(reflect.ValueOf(int(i)), keyType)
+		}
+		if f, err := strconv.ParseFloat(p.literal, 64); err == nil {
+			return p.interfaceKey(reflect.ValueOf(f), keyType)
+		}
+		if b, err := strconv.ParseBool(p.literal); err == nil {
+			return p.interfaceKey(reflect.ValueOf(b), keyType)
+		}
+		return reflect.Value{}, false
+	default:
+		return reflect.Value{}, false
+	}
+	return key, true
+}
+
+// textKeyFor converts a quoted literal into a key, using encoding.TextUnmarshaler if keyType implements it
+func (p pathTypedMapKey) textKeyFor(keyType reflect.Type) (reflect.Value, bool) {
+	text, err := strconv.Unquote(p.literal)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	key := reflect.New(keyType)
+	if unmarshaler, ok := key.Interface().(encoding.TextUnmarshaler); ok {
+		if err = unmarshaler.UnmarshalText([]byte(text)); err != nil {
+			return reflect.Value{}, false
+		}
+		return key.Elem(), true
+	}
+	switch keyType.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(text).Convert(keyType), true
+	case reflect.Interface:
+		return p.interfaceKey(reflect.ValueOf(text), keyType)
+	}
+	return reflect.Value{}, false
+}
+
+func (p pathTypedMapKey) interfaceKey(v reflect.Value, keyType reflect.Type) (reflect.Value, bool) {
+	if !v.Type().Implements(keyType) {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
+
+// mapKeyer is implemented by components that identify map entries
+type mapKeyer interface {
+	Componenter
+	keyFor(keyType reflect.Type) (key reflect.Value, ok bool)
+}
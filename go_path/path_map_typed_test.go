@@ -0,0 +1,196 @@
+package go_path
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testColor int
+
+const (
+	testColorRed testColor = iota
+	testColorBlue
+)
+
+func (c testColor) MarshalText() ([]byte, error) {
+	return []byte([]string{"red", "blue"}[c]), nil
+}
+
+func (c *testColor) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "red":
+		*c = testColorRed
+	case "blue":
+		*c = testColorBlue
+	default:
+		return fmt.Errorf("unknown color %s", text)
+	}
+	return nil
+}
+
+type testLabel string
+
+type testTypedMaps struct {
+	ByID    map[int]string
+	ByFlag  map[bool]string
+	ByColor map[testColor]string
+	ByLabel map[testLabel]string
+	ByAny   map[interface{}]string
+}
+
+func newTestTypedMaps() *testTypedMaps {
+	return &testTypedMaps{
+		ByID:    map[int]string{42: "answer", -1: "negative"},
+		ByFlag:  map[bool]string{true: "yes"},
+		ByColor: map[testColor]string{testColorBlue: "sky"},
+		ByLabel: map[testLabel]string{"a": "label"},
+		ByAny:   map[interface{}]string{7: "int", "7": "string"},
+	}
+}
+
+func TestNewMapKeyOf_String(t *testing.T) {
+	cases := map[string]struct {
+		input    Componenter
+		expected string
+	}{
+		"int": {
+			input:    NewMapKeyOf(42),
+			expected: "[(42)]",
+		},
+		"negative": {
+			input:    NewMapKeyOf(int8(-3)),
+			expected: "[(-3)]",
+		},
+		"float": {
+			input:    NewMapKeyOf(1.5),
+			expected: "[(1.5)]",
+		},
+		"bool": {
+			input:    NewMapKeyOf(true),
+			expected: "[(true)]",
+		},
+		"text marshaler": {
+			input:    NewMapKeyOf(testColorBlue),
+			expected: "[(\"blue\")]",
+		},
+		"string": {
+			input:    NewMapKeyOf("42"),
+			expected: "[\"42\"]",
+		},
+		"named string": {
+			input:    NewMapKeyOf(testLabel("a")),
+			expected: "[\"a\"]",
+		},
+	}
+
+	for caseName, c := range cases {
+		assert.Equal(t, c.expected, c.input.String(), caseName)
+		parsed, err := Parse(bytes.NewBufferString(c.input.String()))
+		require.NoError(t, err, caseName)
+		assert.True(t, New(c.input).IsEqual(parsed), caseName)
+	}
+	assert.False(t, NewMapKeyOf(42).IsEqual(NewMapKey("42")))
+}
+
+func TestParse_TypedMapKeyErrors(t *testing.T) {
+	for _, input := range []string{"[()]", "[(abc)]", "[(42]", "[(\"a)]", "[(42)"} {
+		_, err := Parse(strings.NewReader(input))
+		assert.Error(t, err, input)
+	}
+}
+
+func TestGet_TypedMapKeys(t *testing.T) {
+	cases := map[string]struct {
+		input       string
+		expected    string
+		expectedErr bool
+	}{
+		"int":           {input: "ByID[(42)]", expected: "answer"},
+		"negative int":  {input: "ByID[(-1)]", expected: "negative"},
+		"bool":          {input: "ByFlag[(true)]", expected: "yes"},
+		"text":          {input: "ByColor[(\"blue\")]", expected: "sky"},
+		"named string":  {input: "ByLabel[\"a\"]", expected: "label"},
+		"interface int": {input: "ByAny[(7)]", expected: "int"},
+		"interface str": {input: "ByAny[\"7\"]", expected: "string"},
+		"string key":    {input: "ByID[\"42\"]", expectedErr: true},
+		"bad literal":   {input: "ByID[(1.5)]", expectedErr: true},
+		"bad text":      {input: "ByColor[(\"green\")]", expectedErr: true},
+	}
+
+	for caseName, c := range cases {
+		p, err := Parse(strings.NewReader(c.input))
+		require.NoError(t, err, caseName)
+		actual, err := GetInterface(newTestTypedMaps(), p)
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+		} else {
+			require.NoError(t, err, caseName)
+			assert.Equal(t, c.expected, actual, caseName)
+		}
+	}
+}
+
+func TestGet_BoundTypedMapKeys(t *testing.T) {
+	cases := map[string]struct {
+		root        interface{}
+		key         interface{}
+		expected    string
+		expectedErr bool
+	}{
+		"same type":        {root: map[int]string{1: "one"}, key: 1, expected: "one"},
+		"whole float":      {root: map[int]string{1: "one"}, key: 1.0, expected: "one"},
+		"fraction":         {root: map[int]string{1: "one"}, key: 1.5, expectedErr: true},
+		"in range":         {root: map[int8]string{44: "small"}, key: 44, expected: "small"},
+		"out of range":     {root: map[int8]string{44: "small"}, key: 300, expectedErr: true},
+		"negative to uint": {root: map[uint]string{1: "one"}, key: -1, expectedErr: true},
+	}
+
+	for caseName, c := range cases {
+		actual, err := GetInterface(c.root, New(NewMapKeyOf(c.key)))
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+		} else {
+			require.NoError(t, err, caseName)
+			assert.Equal(t, c.expected, actual, caseName)
+		}
+		// a bound key resolves like its literal does
+		parsed, err := ParseString(NewMapKeyOf(c.key).String())
+		require.NoError(t, err, caseName)
+		_, err = Get(c.root, parsed)
+		assert.Equal(t, c.expectedErr, err != nil, caseName)
+	}
+}
+
+func TestWalk_TypedMapKeys(t *testing.T) {
+	root := newTestTypedMaps()
+	err := Walk(root, func(p Pather, v reflect.Value) error {
+		parsed, err := Parse(strings.NewReader(p.String()))
+		require.NoError(t, err, p.String())
+		actual, err := Get(root, parsed)
+		require.NoError(t, err, p.String())
+		assert.Equal(t, v.Interface(), actual.Interface(), p.String())
+		return nil
+	}, WalkLeavesOnly())
+	require.NoError(t, err)
+}
+
+func TestBind(t *testing.T) {
+	p, err := ParseTyped(strings.NewReader("ByColor[(\"blue\")]"), reflect.TypeOf(&testTypedMaps{}))
+	require.NoError(t, err)
+	var key TypedMapKeyComponenter
+	p.Each(func(index int, componenter Componenter) {
+		if index == 1 {
+			key = componenter.(TypedMapKeyComponenter)
+		}
+	})
+	require.NotNil(t, key)
+	assert.Equal(t, testColorBlue, key.KeyValue())
+
+	_, err = ParseTyped(strings.NewReader("ByID[(true)]"), reflect.TypeOf(&testTypedMaps{}))
+	assert.Error(t, err)
+}
@@ -24,5 +24,17 @@ func (p pathStructInstanceVariable) IsEqual(componenter paths.Componenter) bool
 }
 
 func (p pathStructInstanceVariable) String() string {
+	return quoteFieldName(p.variableName)
+}
+
+func (p pathStructInstanceVariable) Kind() ComponentKind {
+	return ComponentKindStruct
+}
+
+func (p *pathStructInstanceVariable) Accept(visitor ComponentVisitor) error {
+	return visitor.VisitField(p)
+}
+
+func (p pathStructInstanceVariable) FieldName() string {
 	return p.variableName
 }
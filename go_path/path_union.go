@@ -0,0 +1,213 @@
+package go_path
+
+import (
+	"errors"
+	paths "github.com/wojnosystems/go-path"
+	"reflect"
+	"strings"
+)
+
+// pathUnion selects each of several alternative children of a value, in order.
+// Unions of struct fields are serialized in braces, like a field: user.{name,email}. Unions of indexes, map keys and
+// keyed elements are serialized in brackets: items[0,3,7], labels["a","b"]
+type pathUnion struct {
+	alternatives []Componenter
+}
+
+// UnionComponenter is a component that selects each of several alternatives
+type UnionComponenter interface {
+	Componenter
+	// Alternatives are the concrete components that the union selects
+	Alternatives() []Componenter
+	// Braced is true if the alternatives are struct fields, written like a field: {name,email}
+	Braced() bool
+}
+
+// UnionVisitor may be implemented by a ComponentVisitor to visit UnionComponenters
+type UnionVisitor interface {
+	VisitUnion(component UnionComponenter) error
+}
+
+// NewUnion creates a component selecting each of the alternatives, which must either all be struct fields, or all be
+// array indexes, map keys and keyed elements. It returns an error if there are no alternatives, or if they cannot be
+// written together. A single index, map key or keyed element is returned as it is, as it is written the same way as a
+// union of itself
+func NewUnion(alternatives ...Componenter) (Componenter, error) {
+	if err := checkUnionAlternatives(alternatives); err != nil {
+		return nil, err
+	}
+	if _, braced := alternatives[0].(*pathStructInstanceVariable); !braced && len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+	copied := make([]Componenter, len(alternatives))
+	copy(copied, alternatives)
+	return &pathUnion{alternatives: copied}, nil
+}
+
+// MustNewUnion is like NewUnion, but panics if the alternatives cannot form a union.
+// It simplifies creating unions of alternatives known to be valid:
+//
+//	var contact = go_path.New(go_path.NewInstanceVariableNamed("user"), go_path.MustNewUnion(
+//		go_path.NewInstanceVariableNamed("email"), go_path.NewInstanceVariableNamed("phone")))
+func MustNewUnion(alternatives ...Componenter) Componenter {
+	union, err := NewUnion(alternatives...)
+	if err != nil {
+		panic("go_path: MustNewUnion: " + err.Error())
+	}
+	return union
+}
+
+// checkUnionAlternatives returns an error unless the alternatives are all struct fields, or all array indexes, map keys
+// and keyed elements
+func checkUnionAlternatives(alternatives []Componenter) error {
+	if len(alternatives) == 0 {
+		return errors.New("a union must have alternatives")
+	}
+	_, braced := alternatives[0].(*pathStructInstanceVariable)
+	for _, alternative := range alternatives {
+		switch alternative.(type) {
+		case *pathStructInstanceVariable:
+			if braced {
+				continue
+			}
+		case *pathArrayInstanceVariable, mapKeyer, *pathKeyedElement:
+			if !braced {
+				continue
+			}
+		}
+		if braced {
+			return errors.New("union alternatives must all be struct fields, not " + alternative.String())
+		}
+		return errors.New("union alternatives must be array indexes, map keys or keyed elements, not " + alternative.String())
+	}
+	return nil
+}
+
+// newParsedFieldUnion creates a union of the comma separated field names found between braces
+func newParsedFieldUnion(names string) (Componenter, error) {
+	alternatives := make([]Componenter, 0)
+	for _, name := range splitFieldUnion(names) {
+		if name == "" {
+			return nil, errors.New("invalid field union {" + names + "}")
+		}
+		if strings.HasPrefix(name, "'") {
+			name = unquoteFieldName(name[1 : len(name)-1])
+		}
+		alternatives = append(alternatives, NewInstanceVariableNamed(name))
+	}
+	return NewUnion(alternatives...)
+}
+
+// splitFieldUnion splits the names of a field union at the commas that are not quoted
+func splitFieldUnion(names string) []string {
+	split := make([]string, 0)
+	start := 0
+	isQuoted := false
+	isEscaped := false
+	for i, r := range names {
+		switch {
+		case isQuoted:
+			if !isEscaped && '\'' == r {
+				isQuoted = false
+			}
+			isEscaped = !isEscaped && isEscapeChar(r)
+		case '\'' == r:
+			isQuoted = true
+		case ',' == r:
+			split = append(split, names[start:i])
+			start = i + 1
+		}
+	}
+	return append(split, names[start:])
+}
+
+func (p pathUnion) IsEqual(componenter paths.Componenter) bool {
+	if componenter == nil {
+		return false
+	}
+	if component, ok := componenter.(*pathUnion); !ok {
+		return false
+	} else {
+		if len(p.alternatives) != len(component.alternatives) {
+			return false
+		}
+		for i, alternative := range p.alternatives {
+			if !alternative.IsEqual(component.alternatives[i]) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func (p pathUnion) String() string {
+	alternatives := make([]string, len(p.alternatives))
+	for i, alternative := range p.alternatives {
+		alternatives[i] = strings.TrimSuffix(strings.TrimPrefix(alternative.String(), "["), "]")
+	}
+	if p.Braced() {
+		return "{" + strings.Join(alternatives, ",") + "}"
+	}
+	return "[" + strings.Join(alternatives, ",") + "]"
+}
+
+func (p pathUnion) Kind() ComponentKind {
+	return ComponentKindUnion
+}
+
+func (p *pathUnion) Accept(visitor ComponentVisitor) error {
+	if unionVisitor, ok := visitor.(UnionVisitor); ok {
+		return unionVisitor.VisitUnion(p)
+	}
+	return newVisitorUnsupportedError(p)
+}
+
+func (p pathUnion) Alternatives() []Componenter {
+	alternatives := make([]Componenter, len(p.alternatives))
+	copy(alternatives, p.alternatives)
+	return alternatives
+}
+
+func (p pathUnion) Braced() bool {
+	if len(p.alternatives) == 0 {
+		return false
+	}
+	for _, alternative := range p.alternatives {
+		if alternative.Kind() != ComponentKindStruct {
+			return false
+		}
+	}
+	return true
+}
+
+func (p pathUnion) matchesComponent(component Componenter) bool {
+	for _, alternative := range p.alternatives {
+		if alternative.IsEqual(component) {
+			return true
+		}
+	}
+	return false
+}
+
+// expand selects each alternative that resolves against v. It is only an error if none of them do
+func (p *pathUnion) expand(v reflect.Value) ([]expansion, *ResolveError) {
+	expansions := make([]expansion, 0, len(p.alternatives))
+	var firstErr *ResolveError
+	for _, alternative := range p.alternatives {
+		child, err := resolveComponent(v, alternative)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		expansions = append(expansions, expansion{
+			path:  []Componenter{alternative},
+			value: child,
+		})
+	}
+	if len(expansions) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return expansions, nil
+}
@@ -0,0 +1,117 @@
+package go_path
+
+import (
+	paths "github.com/wojnosystems/go-path"
+	"reflect"
+)
+
+// pathWildcard selects every child of a value: the exported fields of a struct, the elements of a slice or array, or
+// the entries of a map, in key order.
+// It is serialized as .* when used like a field and as [*] when used like an index. Both select the same children.
+type pathWildcard struct {
+	bracketed bool
+}
+
+// WildcardComponenter is a component that selects every child of a value
+type WildcardComponenter interface {
+	Componenter
+	// Bracketed is true if the wildcard is written like an index, [*], and false if it is written like a field, .*
+	Bracketed() bool
+}
+
+// WildcardVisitor may be implemented by a ComponentVisitor to visit WildcardComponenters
+type WildcardVisitor interface {
+	VisitWildcard(component WildcardComponenter) error
+}
+
+// NewWildcard creates a component selecting every child of a value, serialized like a field: settings.*
+func NewWildcard() Componenter {
+	return &pathWildcard{}
+}
+
+// NewIndexWildcard creates a component selecting every child of a value, serialized like an index: users[*]
+func NewIndexWildcard() Componenter {
+	return &pathWildcard{bracketed: true}
+}
+
+func (p pathWildcard) IsEqual(componenter paths.Componenter) bool {
+	if componenter == nil {
+		return false
+	}
+	if component, ok := componenter.(*pathWildcard); !ok {
+		return false
+	} else {
+		return p.bracketed == component.bracketed
+	}
+}
+
+func (p pathWildcard) String() string {
+	if p.bracketed {
+		return "[*]"
+	}
+	return "*"
+}
+
+func (p pathWildcard) Kind() ComponentKind {
+	return ComponentKindWildcard
+}
+
+func (p *pathWildcard) Accept(visitor ComponentVisitor) error {
+	if wildcardVisitor, ok := visitor.(WildcardVisitor); ok {
+		return wildcardVisitor.VisitWildcard(p)
+	}
+	return newVisitorUnsupportedError(p)
+}
+
+func (p pathWildcard) Bracketed() bool {
+	return p.bracketed
+}
+
+func (p pathWildcard) matchesComponent(component Componenter) bool {
+	switch component.(type) {
+	case FieldComponenter, IndexComponenter, MapKeyComponenter, KeyedElementComponenter:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *pathWildcard) expand(v reflect.Value) ([]expansion, *ResolveError) {
+	switch v.Kind() {
+	case reflect.Struct:
+		expansions := make([]expansion, 0, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			expansions = append(expansions, expansion{
+				path:  []Componenter{NewInstanceVariableNamed(field.Name)},
+				value: v.Field(i),
+			})
+		}
+		return expansions, nil
+	case reflect.Slice, reflect.Array:
+		expansions := make([]expansion, v.Len())
+		for i := range expansions {
+			expansions[i] = expansion{
+				path:  []Componenter{NewArrayIndex(i)},
+				value: v.Index(i),
+			}
+		}
+		return expansions, nil
+	case reflect.Map:
+		keys := v.MapKeys()
+		sortMapKeys(keys)
+		expansions := make([]expansion, len(keys))
+		for i, key := range keys {
+			expansions[i] = expansion{
+				path:  []Componenter{mapKeyComponent(key)},
+				value: v.MapIndex(key),
+			}
+		}
+		return expansions, nil
+	default:
+		return nil, newResolveError(ResolveReasonKindMismatch, v)
+	}
+}
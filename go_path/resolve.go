@@ -0,0 +1,128 @@
+package go_path
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Get resolves the path against root and returns the value it points at.
+// Structs, slices, arrays and maps are walked component by component. Pointers and interfaces encountered along the
+// way (including root itself) are dereferenced transparently. The returned value is not dereferenced, so a path
+// ending at a pointer field returns the pointer.
+// Failures to resolve are reported as a *ResolveError
+func Get(root interface{}, p Pather) (reflect.Value, error) {
+	v, err := resolve(reflect.ValueOf(root), p)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return v, nil
+}
+
+// resolve is Get for a value that has already been reflected
+func resolve(current reflect.Value, p Pather) (reflect.Value, *ResolveError) {
+	resolved := NewRoot()
+	for _, component := range components(p) {
+		var resolveErr *ResolveError
+		current, resolveErr = indirect(current)
+		if resolveErr != nil {
+			return reflect.Value{}, resolveErr.locate(resolved, component)
+		}
+		current, resolveErr = resolveComponent(current, component)
+		if resolveErr != nil {
+			return reflect.Value{}, resolveErr.locate(resolved, component)
+		}
+		resolved.Append(component)
+	}
+	return current, nil
+}
+
+// GetInterface is Get, but returns the resolved value as an interface{}
+// Values that cannot be interfaced, such as un-exported struct fields, return an error
+func GetInterface(root interface{}, p Pather) (interface{}, error) {
+	v, err := Get(root, p)
+	if err != nil {
+		return nil, err
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if !v.CanInterface() {
+		return nil, fmt.Errorf("value at \"%s\" cannot be accessed (is it un-exported?)", p.String())
+	}
+	return v.Interface(), nil
+}
+
+// components copies the components of the path into a slice so they can be indexed
+func components(p Pather) []Componenter {
+	parts := make([]Componenter, 0)
+	p.Each(func(_ int, componenter Componenter) {
+		parts = append(parts, componenter)
+	})
+	return parts
+}
+
+// indirect follows pointers and interfaces until a value that is neither is found
+func indirect(v reflect.Value) (reflect.Value, *ResolveError) {
+	for {
+		if !v.IsValid() {
+			return v, newResolveError(ResolveReasonNilPointer, v)
+		}
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if v.IsNil() {
+				return v, newResolveError(ResolveReasonNilPointer, v)
+			}
+			v = v.Elem()
+		default:
+			return v, nil
+		}
+	}
+}
+
+// resolveComponent locates the child of v identified by component. v must already be indirected
+// The returned error has not been located within the path yet
+func resolveComponent(v reflect.Value, component Componenter) (reflect.Value, *ResolveError) {
+	switch c := component.(type) {
+	case *pathStructInstanceVariable:
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, newResolveError(ResolveReasonKindMismatch, v)
+		}
+		field := v.FieldByName(c.variableName)
+		if !field.IsValid() {
+			return reflect.Value{}, newResolveError(ResolveReasonNoSuchField, v)
+		}
+		return field, nil
+	case *pathArrayInstanceVariable:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return reflect.Value{}, newResolveError(ResolveReasonKindMismatch, v)
+		}
+		index, ok := c.absoluteIndex(v.Len())
+		if !ok {
+			err := newResolveError(ResolveReasonIndexOutOfRange, v)
+			err.Len = v.Len()
+			return reflect.Value{}, err
+		}
+		return v.Index(index), nil
+	case *pathKeyedElement:
+		index, err := c.indexIn(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return v.Index(index), nil
+	case mapKeyer:
+		if v.Kind() != reflect.Map {
+			return reflect.Value{}, newResolveError(ResolveReasonKindMismatch, v)
+		}
+		key, ok := c.keyFor(v.Type().Key())
+		if !ok {
+			return reflect.Value{}, newResolveError(ResolveReasonKindMismatch, v)
+		}
+		value := v.MapIndex(key)
+		if !value.IsValid() {
+			return reflect.Value{}, newResolveError(ResolveReasonMissingMapKey, v)
+		}
+		return value, nil
+	default:
+		return reflect.Value{}, newResolveError(ResolveReasonUnsupportedComponent, v)
+	}
+}
@@ -0,0 +1,140 @@
+package go_path
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ResolveReason describes why a component of a path could not be resolved against a value
+type ResolveReason uint8
+
+const (
+	ResolveReasonInvalid ResolveReason = iota
+	// ResolveReasonNilPointer a nil pointer, interface or map was found where the path needed to descend
+	ResolveReasonNilPointer
+	// ResolveReasonIndexOutOfRange an array index was outside of the bounds of the slice or array
+	ResolveReasonIndexOutOfRange
+	// ResolveReasonMissingMapKey the map did not contain the key
+	ResolveReasonMissingMapKey
+	// ResolveReasonNoSuchField the struct does not have a field with that name
+	ResolveReasonNoSuchField
+	// ResolveReasonKindMismatch the component cannot be applied to the kind of value found, such as a map key against a slice
+	ResolveReasonKindMismatch
+	// ResolveReasonNotSettable the value was found, but cannot be modified, such as an un-exported field
+	ResolveReasonNotSettable
+	// ResolveReasonUnsupportedComponent the component cannot be used for this operation
+	ResolveReasonUnsupportedComponent
+	// ResolveReasonNoMatch no element of the slice or array has the key of a keyed element
+	ResolveReasonNoMatch
+	// ResolveReasonAmbiguous more than one element of the slice or array has the key of a keyed element
+	ResolveReasonAmbiguous
+)
+
+func (r ResolveReason) String() string {
+	switch r {
+	case ResolveReasonNilPointer:
+		return "nil pointer"
+	case ResolveReasonIndexOutOfRange:
+		return "index out of range"
+	case ResolveReasonMissingMapKey:
+		return "missing map key"
+	case ResolveReasonNoSuchField:
+		return "no such field"
+	case ResolveReasonKindMismatch:
+		return "kind mismatch"
+	case ResolveReasonNotSettable:
+		return "not settable"
+	case ResolveReasonUnsupportedComponent:
+		return "unsupported component"
+	case ResolveReasonNoMatch:
+		return "no match"
+	case ResolveReasonAmbiguous:
+		return "ambiguous"
+	default:
+		return "invalid"
+	}
+}
+
+// ResolveError is returned when a path cannot be resolved against a value
+// Use errors.As to retrieve it from errors returned by Get, Set, Delete and Insert
+type ResolveError struct {
+	// Index of the failing component, as reported by Pather.Each
+	Index int
+	// Component that could not be resolved
+	Component Componenter
+	// Resolved is the prefix of the path that did resolve. It does not include Component
+	Resolved Pather
+	// Kind of the value that Component was resolved against
+	Kind reflect.Kind
+	// Type of the value that Component was resolved against, nil if unknown
+	Type reflect.Type
+	// Len of the slice or array, only set when Reason is ResolveReasonIndexOutOfRange
+	Len int
+	// Reason resolution failed
+	Reason ResolveReason
+}
+
+// newResolveError creates an error describing the value v that component could not be resolved against
+func newResolveError(reason ResolveReason, v reflect.Value) *ResolveError {
+	e := &ResolveError{
+		Reason: reason,
+		Kind:   v.Kind(),
+	}
+	if v.IsValid() {
+		e.Type = v.Type()
+	}
+	return e
+}
+
+// locate records where in the path the error occurred
+func (e *ResolveError) locate(resolved Pather, component Componenter) *ResolveError {
+	e.Resolved = resolved.Copy()
+	e.Index = len(components(resolved))
+	e.Component = component
+	return e
+}
+
+// Path is the prefix of the path that was resolved, including the failing Component
+func (e *ResolveError) Path() Pather {
+	p := NewRoot()
+	if e.Resolved != nil {
+		p = e.Resolved.Copy()
+	}
+	if e.Component != nil {
+		p.Append(e.Component)
+	}
+	return p
+}
+
+func (e *ResolveError) typeName() string {
+	if e.Type == nil {
+		return e.Kind.String()
+	}
+	return e.Type.String()
+}
+
+func (e *ResolveError) Error() string {
+	at := e.Path().String()
+	switch e.Reason {
+	case ResolveReasonNilPointer:
+		return fmt.Sprintf("%s: cannot resolve through nil %s", at, e.typeName())
+	case ResolveReasonIndexOutOfRange:
+		return fmt.Sprintf("%s out of range (len %d)", at, e.Len)
+	case ResolveReasonMissingMapKey:
+		return fmt.Sprintf("%s: no such map key", at)
+	case ResolveReasonNoSuchField:
+		return fmt.Sprintf("%s: no such field in %s", at, e.typeName())
+	case ResolveReasonKindMismatch:
+		return fmt.Sprintf("%s: cannot be resolved against %s", at, e.typeName())
+	case ResolveReasonNotSettable:
+		return fmt.Sprintf("%s: cannot be set (is it un-exported?)", at)
+	case ResolveReasonUnsupportedComponent:
+		return fmt.Sprintf("%s: component is not supported here", at)
+	case ResolveReasonNoMatch:
+		return fmt.Sprintf("%s: no element matches", at)
+	case ResolveReasonAmbiguous:
+		return fmt.Sprintf("%s: more than one element matches", at)
+	default:
+		return fmt.Sprintf("%s: unable to resolve", at)
+	}
+}
@@ -0,0 +1,89 @@
+package go_path
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+func TestResolveError(t *testing.T) {
+	cases := map[string]struct {
+		path          func() Pather
+		expectedIndex int
+		expectedKind  reflect.Kind
+		expected      ResolveReason
+		expectedMsg   string
+	}{
+		"nil pointer": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Pointers"), NewArrayIndex(0), NewInstanceVariableNamed("Name"))
+			},
+			expectedIndex: 2,
+			expectedKind:  reflect.Ptr,
+			expected:      ResolveReasonNilPointer,
+			expectedMsg:   "Pointers[0].Name: cannot resolve through nil *go_path.testDog",
+		},
+		"index out of range": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(4))
+			},
+			expectedIndex: 1,
+			expectedKind:  reflect.Slice,
+			expected:      ResolveReasonIndexOutOfRange,
+			expectedMsg:   "Dogs[4] out of range (len 2)",
+		},
+		"missing map key": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(0), NewInstanceVariableNamed("Attributes"), NewMapKey("paws"))
+			},
+			expectedIndex: 3,
+			expectedKind:  reflect.Map,
+			expected:      ResolveReasonMissingMapKey,
+			expectedMsg:   "Dogs[0].Attributes[\"paws\"]: no such map key",
+		},
+		"no such field": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Cats"))
+			},
+			expectedIndex: 0,
+			expectedKind:  reflect.Struct,
+			expected:      ResolveReasonNoSuchField,
+			expectedMsg:   "Cats: no such field in go_path.testKennel",
+		},
+		"kind mismatch": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewMapKey("rex"))
+			},
+			expectedIndex: 1,
+			expectedKind:  reflect.Slice,
+			expected:      ResolveReasonKindMismatch,
+			expectedMsg:   "Dogs[\"rex\"]: cannot be resolved against []go_path.testDog",
+		},
+	}
+
+	for caseName, c := range cases {
+		p := c.path()
+		_, err := Get(newTestKennel(), p)
+		var resolveErr *ResolveError
+		require.True(t, errors.As(err, &resolveErr), caseName)
+		assert.Equal(t, c.expected, resolveErr.Reason, caseName)
+		assert.Equal(t, c.expectedIndex, resolveErr.Index, caseName)
+		assert.Equal(t, c.expectedKind, resolveErr.Kind, caseName)
+		assert.Equal(t, c.expectedMsg, resolveErr.Error(), caseName)
+
+		expectedResolved := p.Copy()
+		expectedResolved.Pop(uint(len(components(p)) - c.expectedIndex))
+		assert.True(t, expectedResolved.IsEqual(resolveErr.Resolved), caseName)
+	}
+}
+
+func TestResolveError_Set(t *testing.T) {
+	err := Set(&testConfig{}, New(NewInstanceVariableNamed("Servers"), NewArrayIndex(2), NewInstanceVariableNamed("Host")), "a")
+	var resolveErr *ResolveError
+	require.True(t, errors.As(err, &resolveErr))
+	assert.Equal(t, ResolveReasonIndexOutOfRange, resolveErr.Reason)
+	assert.Equal(t, 1, resolveErr.Index)
+	assert.Equal(t, 0, resolveErr.Len)
+}
@@ -0,0 +1,165 @@
+package go_path
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type testAttributes struct {
+	Color string
+	Tags  []string
+}
+
+type testDog struct {
+	Name       string
+	Attributes map[string]testAttributes
+	Owner      *testOwner
+	Extra      interface{}
+	secret     string
+}
+
+type testOwner struct {
+	Name string
+}
+
+type testKennel struct {
+	Dogs     []testDog
+	Pointers []*testDog
+	Counts   [2]int
+}
+
+func newTestKennel() *testKennel {
+	return &testKennel{
+		Dogs: []testDog{
+			{
+				Name: "rex",
+				Attributes: map[string]testAttributes{
+					"fur": {Color: "brown", Tags: []string{"soft", "short"}},
+				},
+				Owner:  &testOwner{Name: "alice"},
+				Extra:  map[string]int{"legs": 4},
+				secret: "bone",
+			},
+			{
+				Name: "fido",
+			},
+		},
+		Pointers: []*testDog{nil},
+		Counts:   [2]int{3, 7},
+	}
+}
+
+func TestGet(t *testing.T) {
+	cases := map[string]struct {
+		path        func() Pather
+		expected    interface{}
+		expectedErr bool
+	}{
+		"root": {
+			path: func() Pather {
+				return NewRoot()
+			},
+			expected: newTestKennel(),
+		},
+		"field": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(1), NewInstanceVariableNamed("Name"))
+			},
+			expected: "fido",
+		},
+		"map in slice": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(0),
+					NewInstanceVariableNamed("Attributes"), NewMapKey("fur"),
+					NewInstanceVariableNamed("Tags"), NewArrayIndex(1))
+			},
+			expected: "short",
+		},
+		"through pointer": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(0), NewInstanceVariableNamed("Owner"), NewInstanceVariableNamed("Name"))
+			},
+			expected: "alice",
+		},
+		"through interface": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(0), NewInstanceVariableNamed("Extra"), NewMapKey("legs"))
+			},
+			expected: 4,
+		},
+		"last element": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(-1), NewInstanceVariableNamed("Name"))
+			},
+			expected: "fido",
+		},
+		"first element from end": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(-2), NewInstanceVariableNamed("Name"))
+			},
+			expected: "rex",
+		},
+		"negative out of range": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(-3))
+			},
+			expectedErr: true,
+		},
+		"array": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Counts"), NewArrayIndex(1))
+			},
+			expected: 7,
+		},
+		"nil pointer": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Pointers"), NewArrayIndex(0), NewInstanceVariableNamed("Name"))
+			},
+			expectedErr: true,
+		},
+		"index out of range": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(2))
+			},
+			expectedErr: true,
+		},
+		"missing key": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(0), NewInstanceVariableNamed("Attributes"), NewMapKey("paws"))
+			},
+			expectedErr: true,
+		},
+		"no such field": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Cats"))
+			},
+			expectedErr: true,
+		},
+		"kind mismatch": {
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Dogs"), NewMapKey("rex"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		actual, err := GetInterface(newTestKennel(), c.path())
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+		} else {
+			require.NoError(t, err, caseName)
+			assert.Equal(t, c.expected, actual, caseName)
+		}
+	}
+}
+
+func TestGet_UnexportedField(t *testing.T) {
+	p := New(NewInstanceVariableNamed("Dogs"), NewArrayIndex(0), NewInstanceVariableNamed("secret"))
+	v, err := Get(newTestKennel(), p)
+	require.NoError(t, err)
+	assert.Equal(t, "bone", v.String())
+	_, err = GetInterface(newTestKennel(), p)
+	assert.Error(t, err)
+}
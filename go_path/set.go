@@ -0,0 +1,171 @@
+package go_path
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// SetOption configures how Set treats missing intermediate containers
+type SetOption func(*setOptions)
+
+type setOptions struct {
+	allocatePointers bool
+	createMaps       bool
+	growSlices       bool
+	// maxGrownLen is the length slices may be grown to
+	maxGrownLen int
+}
+
+// DefaultMaxGrownSliceLen is the length SetGrowSlices may grow slices to
+const DefaultMaxGrownSliceLen = 1 << 16
+
+// SetAllocatePointers allocates a new zero value for nil pointers found along the path
+func SetAllocatePointers() SetOption {
+	return func(o *setOptions) {
+		o.allocatePointers = true
+	}
+}
+
+// SetCreateMaps makes nil maps found along the path and creates missing map entries that the path descends through
+func SetCreateMaps() SetOption {
+	return func(o *setOptions) {
+		o.createMaps = true
+	}
+}
+
+// SetGrowSlices grows slices when an array index is past the end of the slice. New elements are zero values.
+// Slices are grown to at most DefaultMaxGrownSliceLen elements, larger indexes are out of range, see SetGrowSlicesTo
+func SetGrowSlices() SetOption {
+	return SetGrowSlicesTo(DefaultMaxGrownSliceLen)
+}
+
+// SetGrowSlicesTo grows slices like SetGrowSlices, to at most maxLen elements
+func SetGrowSlicesTo(maxLen int) SetOption {
+	return func(o *setOptions) {
+		o.growSlices = true
+		o.maxGrownLen = maxLen
+	}
+}
+
+// SetAutoVivify enables every option that creates missing intermediate containers
+func SetAutoVivify() SetOption {
+	return func(o *setOptions) {
+		SetAllocatePointers()(o)
+		SetCreateMaps()(o)
+		SetGrowSlices()(o)
+	}
+}
+
+// Set writes value to the location identified by the path.
+// root must be a non-nil pointer so that the value it points at can be modified. value must be assignable, or
+// convertible between types of the same kind, to the type at the path. Numbers are converted between numeric types when
+// the conversion does not lose information, so 1.5 cannot be set into an int, nor 300 into an int8. A nil value sets the zero value.
+// Without options, every container along the path must already exist; see SetAutoVivify. Negative array indexes
+// count back from the end of the slice and never grow it.
+func Set(root interface{}, p Pather, value interface{}, opts ...SetOption) error {
+	o := setOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	rootValue, err := settableRoot(root)
+	if err != nil {
+		return err
+	}
+	newValue := reflect.ValueOf(value)
+	parts := components(p)
+	if len(parts) == 0 {
+		return assign(rootValue, newValue, NewRoot())
+	}
+	return editAt(rootValue, parts, NewRoot(), &o, func(container reflect.Value, last Componenter, resolved PathMutator) error {
+		return descend(container, last, resolved, &o, true, func(child reflect.Value) error {
+			return assign(child, newValue, resolved)
+		})
+	})
+}
+
+// assign sets target to value, converting value when needed
+func assign(target reflect.Value, value reflect.Value, resolved Pather) error {
+	converted, err := assignableValue(value, target.Type())
+	if err != nil {
+		return fmt.Errorf("unable to set \"%s\": %s", resolved.String(), err.Error())
+	}
+	target.Set(converted)
+	return nil
+}
+
+// assignableValue converts value so that it may be assigned to a location of type t
+func assignableValue(value reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if !value.IsValid() {
+		return reflect.Zero(t), nil
+	}
+	if value.Type().AssignableTo(t) {
+		return value, nil
+	}
+	if isNumericKind(value.Kind()) && isNumericKind(t.Kind()) {
+		if converted, ok := convertNumber(value, t); ok {
+			return converted, nil
+		}
+		return reflect.Value{}, fmt.Errorf("value %v of type %s cannot be represented by %s", value, value.Type().String(), t.String())
+	}
+	if value.Kind() == t.Kind() && value.Type().ConvertibleTo(t) {
+		return value.Convert(t), nil
+	}
+	return reflect.Value{}, fmt.Errorf("value of type %s is not assignable to %s", value.Type().String(), t.String())
+}
+
+// convertNumber converts a number to the numeric type t, unless that loses information: fractions are not truncated,
+// numbers out of the range of t do not wrap around and integers are not rounded into floats. Floats may be rounded
+// into a float type with less precision, as long as they are within its range
+func convertNumber(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	converted := value.Convert(t)
+	if isNegativeNumber(converted) != isNegativeNumber(value) {
+		return reflect.Value{}, false
+	}
+	back := converted.Convert(value.Type())
+	switch {
+	case isIntegerKind(value.Kind()) && isSignedKind(value.Kind()):
+		return converted, back.Int() == value.Int()
+	case isIntegerKind(value.Kind()):
+		return converted, back.Uint() == value.Uint()
+	case isFloatKind(t.Kind()):
+		// NaN and infinities convert to themselves, finite floats must not overflow into infinities
+		return converted, !math.IsInf(converted.Float(), 0) || math.IsInf(value.Float(), 0)
+	default:
+		// NaN is never equal to itself, so it is rejected
+		return converted, back.Float() == value.Float()
+	}
+}
+
+func isNegativeNumber(v reflect.Value) bool {
+	switch {
+	case isIntegerKind(v.Kind()) && isSignedKind(v.Kind()):
+		return v.Int() < 0
+	case isIntegerKind(v.Kind()):
+		return false
+	default:
+		return v.Float() < 0
+	}
+}
+
+func isSignedKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
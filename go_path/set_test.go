@@ -0,0 +1,346 @@
+package go_path
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type testTLS struct {
+	Cert string
+}
+
+type testServer struct {
+	Host string
+	Port int
+	TLS  *testTLS
+}
+
+type testConfig struct {
+	Servers []testServer
+	Labels  map[string]string
+	Nested  map[string]testServer
+	Ratio   float64
+}
+
+func TestSet(t *testing.T) {
+	cases := map[string]struct {
+		input       func() *testConfig
+		path        func() Pather
+		value       interface{}
+		opts        []SetOption
+		expected    func() *testConfig
+		expectedErr bool
+	}{
+		"existing field": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(0), NewInstanceVariableNamed("Host"))
+			},
+			value: "b",
+			expected: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "b"}}}
+			},
+		},
+		"converts numbers": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Ratio"))
+			},
+			value: 2,
+			expected: func() *testConfig {
+				return &testConfig{Ratio: 2}
+			},
+		},
+		"auto vivify": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(3), NewInstanceVariableNamed("TLS"), NewInstanceVariableNamed("Cert"))
+			},
+			value: "pem",
+			opts:  []SetOption{SetAutoVivify()},
+			expected: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {}, {}, {TLS: &testTLS{Cert: "pem"}}}}
+			},
+		},
+		"no grow": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(3), NewInstanceVariableNamed("Host"))
+			},
+			value:       "b",
+			opts:        []SetOption{SetAllocatePointers(), SetCreateMaps()},
+			expectedErr: true,
+		},
+		"no allocate": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(0), NewInstanceVariableNamed("TLS"), NewInstanceVariableNamed("Cert"))
+			},
+			value:       "pem",
+			expectedErr: true,
+		},
+		"nil map": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Labels"), NewMapKey("env"))
+			},
+			value: "prod",
+			opts:  []SetOption{SetCreateMaps()},
+			expected: func() *testConfig {
+				return &testConfig{Labels: map[string]string{"env": "prod"}}
+			},
+		},
+		"nil map not created": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Labels"), NewMapKey("env"))
+			},
+			value:       "prod",
+			expectedErr: true,
+		},
+		"inside map value": {
+			input: func() *testConfig {
+				return &testConfig{Nested: map[string]testServer{"x": {Host: "a", Port: 1}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Nested"), NewMapKey("x"), NewInstanceVariableNamed("Port"))
+			},
+			value: 2,
+			expected: func() *testConfig {
+				return &testConfig{Nested: map[string]testServer{"x": {Host: "a", Port: 2}}}
+			},
+		},
+		"negative index": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {Host: "b"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(-1), NewInstanceVariableNamed("Host"))
+			},
+			value: "c",
+			expected: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}, {Host: "c"}}}
+			},
+		},
+		"negative index does not grow": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(-2), NewInstanceVariableNamed("Host"))
+			},
+			value:       "c",
+			opts:        []SetOption{SetAutoVivify()},
+			expectedErr: true,
+		},
+		"grow past the limit": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(3), NewInstanceVariableNamed("Host"))
+			},
+			value:       "c",
+			opts:        []SetOption{SetGrowSlicesTo(3)},
+			expectedErr: true,
+		},
+		"grow to the limit": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(2), NewInstanceVariableNamed("Host"))
+			},
+			value: "c",
+			opts:  []SetOption{SetGrowSlicesTo(3)},
+			expected: func() *testConfig {
+				return &testConfig{Servers: []testServer{{}, {}, {Host: "c"}}}
+			},
+		},
+		"grow to the largest index": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(math.MaxInt64), NewInstanceVariableNamed("Host"))
+			},
+			value:       "c",
+			opts:        []SetOption{SetAutoVivify()},
+			expectedErr: true,
+		},
+		"grow to a huge index": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(1<<62), NewInstanceVariableNamed("Host"))
+			},
+			value:       "c",
+			opts:        []SetOption{SetAutoVivify()},
+			expectedErr: true,
+		},
+		"loses a fraction": {
+			input: func() *testConfig {
+				return &testConfig{Servers: []testServer{{Host: "a"}}}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Servers"), NewArrayIndex(0), NewInstanceVariableNamed("Port"))
+			},
+			value:       1.5,
+			expectedErr: true,
+		},
+		"type mismatch": {
+			input: func() *testConfig {
+				return &testConfig{}
+			},
+			path: func() Pather {
+				return New(NewInstanceVariableNamed("Ratio"))
+			},
+			value:       "one",
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		actual := c.input()
+		err := Set(actual, c.path(), c.value, c.opts...)
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+		} else {
+			require.NoError(t, err, caseName)
+			assert.Equal(t, c.expected(), actual, caseName)
+		}
+	}
+}
+
+func TestSet_RootNotPointer(t *testing.T) {
+	err := Set(testConfig{}, New(NewInstanceVariableNamed("Ratio")), 1.0)
+	assert.Error(t, err)
+}
+
+func TestSet_ThroughInterface(t *testing.T) {
+	root := map[string]interface{}{
+		"server": testServer{Host: "a"},
+	}
+	err := Set(&root, New(NewMapKey("server"), NewInstanceVariableNamed("Host")), "b")
+	require.NoError(t, err)
+	assert.Equal(t, testServer{Host: "b"}, root["server"])
+}
+
+func TestAssignableValue_Numbers(t *testing.T) {
+	cases := map[string]struct {
+		value       interface{}
+		target      interface{}
+		expected    interface{}
+		expectedErr bool
+	}{
+		"int to float": {
+			value:    2,
+			target:   float64(0),
+			expected: float64(2),
+		},
+		"whole float to int": {
+			value:    2.0,
+			target:   0,
+			expected: 2,
+		},
+		"fraction to int": {
+			value:       1.5,
+			target:      0,
+			expectedErr: true,
+		},
+		"in range": {
+			value:    127,
+			target:   int8(0),
+			expected: int8(127),
+		},
+		"out of range": {
+			value:       300,
+			target:      int8(0),
+			expectedErr: true,
+		},
+		"negative to unsigned": {
+			value:       -1,
+			target:      uint(0),
+			expectedErr: true,
+		},
+		"large unsigned to signed": {
+			value:       uint64(math.MaxUint64),
+			target:      int64(0),
+			expectedErr: true,
+		},
+		"float past int64": {
+			value:       math.Pow(2, 63),
+			target:      int64(0),
+			expectedErr: true,
+		},
+		"int past float precision": {
+			value:       int64(1<<53 + 1),
+			target:      float64(0),
+			expectedErr: true,
+		},
+		"float32 precision": {
+			value:    0.1,
+			target:   float32(0),
+			expected: float32(0.1),
+		},
+		"float32 out of range": {
+			value:       1e300,
+			target:      float32(0),
+			expectedErr: true,
+		},
+		"infinity to float32": {
+			value:    math.Inf(-1),
+			target:   float32(0),
+			expected: float32(math.Inf(-1)),
+		},
+		"exact float32": {
+			value:    0.5,
+			target:   float32(0),
+			expected: float32(0.5),
+		},
+		"NaN": {
+			value:  math.NaN(),
+			target: float32(0),
+		},
+		"NaN to unsigned": {
+			value:       math.NaN(),
+			target:      uint(0),
+			expectedErr: true,
+		},
+		"NaN to int": {
+			value:       math.NaN(),
+			target:      0,
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		actual, err := assignableValue(reflect.ValueOf(c.value), reflect.TypeOf(c.target))
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+		} else {
+			require.NoError(t, err, caseName)
+			if c.expected != nil {
+				assert.Equal(t, c.expected, actual.Interface(), caseName)
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package go_path
+
+import "fmt"
+
+// ComponentVisitor has a method for each kind of component. Use it with Componenter.Accept or VisitPath to handle each
+// component according to its kind without type assertions.
+// Components that select several values, such as RangeComponenter, have their own visitor interfaces that a
+// ComponentVisitor may also implement. Accepting a visitor that does not implement it returns an error
+type ComponentVisitor interface {
+	VisitField(component FieldComponenter) error
+	VisitIndex(component IndexComponenter) error
+	VisitMapKey(component MapKeyComponenter) error
+}
+
+// VisitPath calls Accept on each component of the path, in order, stopping at the first error
+func VisitPath(p Pather, visitor ComponentVisitor) error {
+	for _, component := range components(p) {
+		if err := component.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newVisitorUnsupportedError(component Componenter) error {
+	return fmt.Errorf("visitor does not support %s components such as %s", component.Kind().String(), component.String())
+}
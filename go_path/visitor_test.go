@@ -0,0 +1,85 @@
+package go_path
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testPointerVisitor translates a path into a JSON-pointer-like string
+type testPointerVisitor struct {
+	sb strings.Builder
+}
+
+func (v *testPointerVisitor) VisitField(component FieldComponenter) error {
+	v.sb.WriteString("/" + component.FieldName())
+	return nil
+}
+
+func (v *testPointerVisitor) VisitIndex(component IndexComponenter) error {
+	if component.Index() < 0 {
+		return errors.New("negative index")
+	}
+	v.sb.WriteString("/" + strconv.Itoa(component.Index()))
+	return nil
+}
+
+func (v *testPointerVisitor) VisitMapKey(component MapKeyComponenter) error {
+	v.sb.WriteString("/" + component.Key())
+	return nil
+}
+
+func TestVisitPath(t *testing.T) {
+	cases := map[string]struct {
+		input       func() Pather
+		expected    string
+		expectedErr bool
+	}{
+		"empty": {
+			input: func() Pather {
+				return NewRoot()
+			},
+			expected: "",
+		},
+		"every element": {
+			input: func() Pather {
+				return New(NewInstanceVariableNamed("dogs"), NewArrayIndex(10), NewMapKey("attributes"))
+			},
+			expected: "/dogs/10/attributes",
+		},
+		"visitor error": {
+			input: func() Pather {
+				return New(NewInstanceVariableNamed("dogs"), NewArrayIndex(-1))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		visitor := &testPointerVisitor{}
+		err := VisitPath(c.input(), visitor)
+		if c.expectedErr {
+			assert.Error(t, err, caseName)
+		} else {
+			require.NoError(t, err, caseName)
+			assert.Equal(t, c.expected, visitor.sb.String(), caseName)
+		}
+	}
+}
+
+func TestComponenter_Kind(t *testing.T) {
+	field := NewInstanceVariableNamed("dogs")
+	assert.Equal(t, ComponentKindStruct, field.Kind())
+	assert.Equal(t, "dogs", field.(FieldComponenter).FieldName())
+
+	index := NewArrayIndex(3)
+	assert.Equal(t, ComponentKindArray, index.Kind())
+	assert.Equal(t, 3, index.(IndexComponenter).Index())
+
+	key := NewMapKey("fur")
+	assert.Equal(t, ComponentKindMap, key.Kind())
+	assert.Equal(t, "fur", key.(MapKeyComponenter).Key())
+}
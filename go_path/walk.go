@@ -0,0 +1,298 @@
+package go_path
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// SkipDir may be returned by a WalkFunc to skip the children of the value it was called with.
+// When returned for a value without children, it has no effect.
+var SkipDir = errors.New("skip this container")
+
+// WalkFunc is called by Walk for each value visited. The path is a copy and may be retained.
+// Returning SkipDir skips the children of v; any other error stops the walk and is returned by Walk.
+type WalkFunc func(p Pather, v reflect.Value) error
+
+// WalkOption configures how Walk traverses a value
+type WalkOption func(*walkOptions)
+
+type walkOptions struct {
+	maxDepth       int
+	leavesOnly     bool
+	skipUnexported bool
+	followPointers bool
+	sortedMapKeys  bool
+	cycleMode      CycleMode
+	visitOnce      bool
+}
+
+// WalkMaxDepth stops descending once a path has depth components. The root is at depth 0
+func WalkMaxDepth(depth int) WalkOption {
+	return func(o *walkOptions) {
+		o.maxDepth = depth
+	}
+}
+
+// WalkLeavesOnly only calls the WalkFunc for values that have no children to visit
+func WalkLeavesOnly() WalkOption {
+	return func(o *walkOptions) {
+		o.leavesOnly = true
+	}
+}
+
+// WalkSkipUnexported does not visit un-exported struct fields
+func WalkSkipUnexported() WalkOption {
+	return func(o *walkOptions) {
+		o.skipUnexported = true
+	}
+}
+
+// WalkFollowPointers descends into the values that pointers point at. Without it, pointers are not descended into.
+// Pointers passed as the root are always followed.
+func WalkFollowPointers() WalkOption {
+	return func(o *walkOptions) {
+		o.followPointers = true
+	}
+}
+
+// WalkSortedMapKeys visits map entries in the order of their keys, instead of Go's random map order
+func WalkSortedMapKeys() WalkOption {
+	return func(o *walkOptions) {
+		o.sortedMapKeys = true
+	}
+}
+
+// WalkOnCycle sets how pointers, maps and slices that refer back to a value being walked are handled. Defaults to
+// CycleModeSkip
+func WalkOnCycle(mode CycleMode) WalkOption {
+	return func(o *walkOptions) {
+		o.cycleMode = mode
+	}
+}
+
+// WalkVisitOnce descends into the value behind each pointer address at most once. Later pointers to an address that
+// was already walked, such as pointers shared between two parents, are handled as cycles are; see WalkOnCycle
+func WalkVisitOnce() WalkOption {
+	return func(o *walkOptions) {
+		o.visitOnce = true
+	}
+}
+
+// Walk visits root and every struct field, slice or array element and map entry within it, depth-first, calling fn
+// with the path to each value. Interfaces are always descended into.
+// Pointers, maps and slices are tracked by address while they are walked, so values that contain themselves do not loop
+// forever.
+func Walk(root interface{}, fn WalkFunc, opts ...WalkOption) error {
+	w := walker{
+		fn: fn,
+		options: walkOptions{
+			maxDepth: -1,
+		},
+		ancestors: make(map[pointerKey]Pather),
+		visited:   make(map[pointerKey]Pather),
+	}
+	for _, opt := range opts {
+		opt(&w.options)
+	}
+	rootValue := reflect.ValueOf(root)
+	for rootValue.Kind() == reflect.Ptr && !rootValue.IsNil() {
+		if isReference(rootValue) {
+			w.ancestors[newPointerKey(rootValue)] = NewRoot()
+		}
+		rootValue = rootValue.Elem()
+	}
+	err := w.walk(rootValue, NewRoot(), 0)
+	if err == SkipDir {
+		return nil
+	}
+	return err
+}
+
+type walker struct {
+	fn      WalkFunc
+	options walkOptions
+	// ancestors are the pointers, maps and slices being walked by the current path, and where they were first seen
+	ancestors map[pointerKey]Pather
+	// visited are all pointers followed so far, only tracked with WalkVisitOnce
+	visited map[pointerKey]Pather
+}
+
+// walkChild is a value found inside of a container and the component that locates it
+type walkChild struct {
+	component Componenter
+	value     reflect.Value
+}
+
+func (w *walker) walk(v reflect.Value, p PathMutator, depth int) error {
+	if key, ok := w.followedReference(v); ok {
+		// maps and slices may be shared without being a cycle, so only pointers are visited once
+		visitOnce := w.options.visitOnce && key.typ.Kind() == reflect.Ptr
+		original, isCycle := w.ancestors[key]
+		if !isCycle && visitOnce {
+			original, isCycle = w.visited[key]
+		}
+		if isCycle {
+			return w.revisit(p, original, v)
+		}
+		w.ancestors[key] = p.Copy()
+		defer delete(w.ancestors, key)
+		if visitOnce {
+			w.visited[key] = w.ancestors[key]
+		}
+	}
+
+	var children []walkChild
+	if w.options.maxDepth < 0 || depth < w.options.maxDepth {
+		children = w.children(v)
+	}
+	if !w.options.leavesOnly || len(children) == 0 {
+		if err := w.fn(p.Copy(), v); err != nil {
+			return err
+		}
+	}
+	for _, child := range children {
+		p.Append(child.component)
+		err := w.walk(child.value, p, depth+1)
+		p.Pop(1)
+		if err == SkipDir {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// followedReference returns the key of the pointer, map or slice in v if the walk will descend through it
+func (w *walker) followedReference(v reflect.Value) (key pointerKey, ok bool) {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !isReference(v) || (v.Kind() == reflect.Ptr && !w.options.followPointers) {
+		return
+	}
+	return newPointerKey(v), true
+}
+
+// revisit handles a pointer to a value that was already walked, found at p and first seen at original
+func (w *walker) revisit(p PathMutator, original Pather, v reflect.Value) error {
+	switch w.options.cycleMode {
+	case CycleModeError:
+		return &CycleError{
+			Original: original,
+			Revisit:  p.Copy(),
+		}
+	case CycleModeReference:
+		return w.fn(p.Copy(), reflect.ValueOf(Reference{Original: original}))
+	default:
+		return w.fn(p.Copy(), v)
+	}
+}
+
+// children lists the values directly contained by v
+func (w *walker) children(v reflect.Value) []walkChild {
+	for v.Kind() == reflect.Interface || (v.Kind() == reflect.Ptr && w.options.followPointers) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		children := make([]walkChild, 0, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if w.options.skipUnexported && field.PkgPath != "" {
+				continue
+			}
+			children = append(children, walkChild{
+				component: NewInstanceVariableNamed(field.Name),
+				value:     v.Field(i),
+			})
+		}
+		return children
+	case reflect.Slice, reflect.Array:
+		children := make([]walkChild, v.Len())
+		for i := range children {
+			children[i] = walkChild{
+				component: NewArrayIndex(i),
+				value:     v.Index(i),
+			}
+		}
+		return children
+	case reflect.Map:
+		keys := v.MapKeys()
+		if w.options.sortedMapKeys {
+			sortMapKeys(keys)
+		}
+		children := make([]walkChild, len(keys))
+		for i, key := range keys {
+			children[i] = walkChild{
+				component: mapKeyComponent(key),
+				value:     v.MapIndex(key),
+			}
+		}
+		return children
+	}
+	return nil
+}
+
+// mapKeyComponent creates the component identifying the map entry with key.
+// Keys of maps found through un-exported fields cannot be returned as interfaces, so they are copied by kind into a new
+// key of the same type first. Keys of other kinds are then identified by their formatted value
+func mapKeyComponent(key reflect.Value) Componenter {
+	if key.Kind() == reflect.Interface {
+		key = key.Elem()
+	}
+	if key.Kind() == reflect.String {
+		return NewMapKey(key.String())
+	}
+	if !key.CanInterface() {
+		copied := reflect.New(key.Type()).Elem()
+		switch key.Kind() {
+		case reflect.Bool:
+			copied.SetBool(key.Bool())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			copied.SetInt(key.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			copied.SetUint(key.Uint())
+		case reflect.Float32, reflect.Float64:
+			copied.SetFloat(key.Float())
+		default:
+			return &pathTypedMapKey{literal: strconv.Quote(fmt.Sprint(key))}
+		}
+		key = copied
+	}
+	return NewMapKeyOf(key.Interface())
+}
+
+// sortMapKeys orders map keys: numbers numerically, strings lexically and everything else by their formatted value
+func sortMapKeys(keys []reflect.Value) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Kind() == reflect.Interface {
+			a = a.Elem()
+		}
+		if b.Kind() == reflect.Interface {
+			b = b.Elem()
+		}
+		if a.Kind() == b.Kind() {
+			switch a.Kind() {
+			case reflect.String:
+				return a.String() < b.String()
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return a.Int() < b.Int()
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				return a.Uint() < b.Uint()
+			case reflect.Float32, reflect.Float64:
+				return a.Float() < b.Float()
+			}
+		}
+		// fmt formats the value held by a reflect.Value, even one found through an un-exported field
+		return fmt.Sprint(a) < fmt.Sprint(b)
+	})
+}
@@ -0,0 +1,145 @@
+package go_path
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+type testWalkInner struct {
+	Value int
+}
+
+type testWalkRoot struct {
+	Name    string
+	Items   []int
+	Labels  map[string]string
+	Inner   *testWalkInner
+	private bool
+}
+
+func newTestWalkRoot() *testWalkRoot {
+	return &testWalkRoot{
+		Name:   "root",
+		Items:  []int{1, 2},
+		Labels: map[string]string{"b": "2", "a": "1"},
+		Inner:  &testWalkInner{Value: 3},
+	}
+}
+
+func TestWalk(t *testing.T) {
+	cases := map[string]struct {
+		opts     []WalkOption
+		skip     string
+		expected []string
+	}{
+		"default": {
+			opts: []WalkOption{WalkSortedMapKeys()},
+			expected: []string{
+				"", "Name", "Items", "Items[0]", "Items[1]",
+				"Labels", "Labels[\"a\"]", "Labels[\"b\"]", "Inner", "private",
+			},
+		},
+		"follow pointers": {
+			opts: []WalkOption{WalkSortedMapKeys(), WalkFollowPointers(), WalkSkipUnexported()},
+			expected: []string{
+				"", "Name", "Items", "Items[0]", "Items[1]",
+				"Labels", "Labels[\"a\"]", "Labels[\"b\"]", "Inner", "Inner.Value",
+			},
+		},
+		"leaves only": {
+			opts: []WalkOption{WalkSortedMapKeys(), WalkFollowPointers(), WalkLeavesOnly(), WalkSkipUnexported()},
+			expected: []string{
+				"Name", "Items[0]", "Items[1]", "Labels[\"a\"]", "Labels[\"b\"]", "Inner.Value",
+			},
+		},
+		"max depth": {
+			opts: []WalkOption{WalkMaxDepth(1), WalkFollowPointers()},
+			expected: []string{
+				"", "Name", "Items", "Labels", "Inner", "private",
+			},
+		},
+		"skip dir": {
+			opts: []WalkOption{WalkSortedMapKeys(), WalkSkipUnexported()},
+			skip: "Items",
+			expected: []string{
+				"", "Name", "Items", "Labels", "Labels[\"a\"]", "Labels[\"b\"]", "Inner",
+			},
+		},
+	}
+
+	for caseName, c := range cases {
+		actual := make([]string, 0)
+		err := Walk(newTestWalkRoot(), func(p Pather, v reflect.Value) error {
+			actual = append(actual, p.String())
+			if c.skip != "" && p.String() == c.skip {
+				return SkipDir
+			}
+			return nil
+		}, c.opts...)
+		require.NoError(t, err, caseName)
+		assert.Equal(t, c.expected, actual, caseName)
+	}
+}
+
+type testWalkLevel int
+
+type testWalkPrivateMaps struct {
+	counts map[int]int
+	levels map[testWalkLevel]bool
+	points map[[2]int]string
+	flags  map[interface{}]bool
+}
+
+func TestWalk_UnexportedMapKeys(t *testing.T) {
+	root := &testWalkPrivateMaps{
+		counts: map[int]int{10: 1, 2: 2},
+		levels: map[testWalkLevel]bool{3: true},
+		points: map[[2]int]string{{1, 2}: "a"},
+		flags:  map[interface{}]bool{true: true, 1.5: false},
+	}
+	actual := make([]string, 0)
+	err := Walk(root, func(p Pather, v reflect.Value) error {
+		actual = append(actual, p.String())
+		return nil
+	}, WalkSortedMapKeys(), WalkFollowPointers())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"", "counts", "counts[(2)]", "counts[(10)]", "levels", "levels[(3)]",
+		"points", `points[("[1 2]")]`, "flags", "flags[(1.5)]", "flags[(true)]",
+	}, actual)
+
+	key, ok := components(New(mapKeyComponent(reflect.ValueOf(root).Elem().Field(1).MapKeys()[0])))[0].(TypedMapKeyComponenter)
+	require.True(t, ok)
+	assert.Equal(t, testWalkLevel(3), key.KeyValue())
+}
+
+func TestWalk_PathsResolve(t *testing.T) {
+	root := newTestWalkRoot()
+	err := Walk(root, func(p Pather, v reflect.Value) error {
+		if len(components(p)) == 0 {
+			return nil
+		}
+		actual, err := Get(root, p)
+		require.NoError(t, err, p.String())
+		assert.Equal(t, v.Interface(), actual.Interface(), p.String())
+		return nil
+	}, WalkFollowPointers(), WalkSkipUnexported())
+	require.NoError(t, err)
+}
+
+func TestWalk_StopsOnError(t *testing.T) {
+	stop := errors.New("stop")
+	count := 0
+	err := Walk(newTestWalkRoot(), func(p Pather, v reflect.Value) error {
+		count++
+		if p.String() == "Items" {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 3, count)
+}